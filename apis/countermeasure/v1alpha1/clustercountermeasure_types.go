@@ -0,0 +1,79 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterCounterMeasureLabel is the name of the ClusterCounterMeasure that created a CounterMeasureRun
+const ClusterCounterMeasureLabel = "countermeasure.vilaverde.rocks/clustercountermeasure"
+
+// ClusterCounterMeasureSpec defines the desired state of ClusterCounterMeasure
+type ClusterCounterMeasureSpec struct {
+	CounterMeasureSpec `json:",inline"`
+
+	// Selects the namespaces the rendered targets of the actions may be in, use an empty selector
+	// to select every namespace. When not set only cluster scoped objects can be targeted, unless
+	// the operator runs with --allow-cross-namespace-targets, in which case every namespace can be.
+	// +kubebuilder:validation:Optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// AsCounterMeasure converts to a CounterMeasure with no namespace, the form the actions are run in.
+func (c *ClusterCounterMeasure) AsCounterMeasure() *CounterMeasure {
+	return &CounterMeasure{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       "ClusterCounterMeasure",
+		},
+		ObjectMeta: *c.ObjectMeta.DeepCopy(),
+		Spec:       *c.Spec.CounterMeasureSpec.DeepCopy(),
+		Status:     *c.Status.DeepCopy(),
+	}
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// ClusterCounterMeasure is the Schema for the clustercountermeasures API
+// +kubebuilder:printcolumn:name="Dry Run",type=boolean,JSONPath=`.spec.dryRun`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.lastStatus`
+// +kubebuilder:printcolumn:name="Status Last Changed",type=string,JSONPath=`.status.lastStatusChangeTime`
+// +kubebuilder:printcolumn:name="Last Verification",type=string,JSONPath=`.status.lastVerification`,priority=1
+// +kubebuilder:resource:scope=Cluster,shortName=cctm
+// +kubebuilder:singular=clustercountermeasure
+type ClusterCounterMeasure struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterCounterMeasureSpec `json:"spec,omitempty"`
+	Status CounterMeasureStatus      `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterCounterMeasureList contains a list of ClusterCounterMeasure
+type ClusterCounterMeasureList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterCounterMeasure `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterCounterMeasure{}, &ClusterCounterMeasureList{})
+}
@@ -28,6 +28,17 @@ const (
 	ReasonResourceNotAvailable = "ResourceNotAvailable"
 
 	TypeMonitoring = "Monitoring"
+	TypeTripped    = "Tripped"
+
+	ReasonTooManyFailures = "TooManyFailures"
+	ReasonReset           = "Reset"
+	ReasonCooledDown      = "CooledDown"
+
+	// ProtectedLabel objects labeled with "true" are never acted on by a guarded action
+	ProtectedLabel = "countermeasures/protected"
+
+	// ResetCircuitBreakerAnnotation can be set on a CounterMeasure to reset a tripped circuit breaker
+	ResetCircuitBreakerAnnotation = "countermeasure.vilaverde.rocks/reset-circuit-breaker"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -60,10 +71,33 @@ type DeploymentReference struct {
 	Name string `json:"name"`
 }
 
+// SuppressionMode defines what is considered a duplicate by a suppression policy
+// +kubebuilder:validation:Enum=Event;Target
+type SuppressionMode string
+
+const (
+	// SuppressByEvent suppresses events with the same key
+	SuppressByEvent SuppressionMode = "Event"
+	// SuppressByTarget suppresses events whose actions target the same objects, whichever event it was
+	SuppressByTarget SuppressionMode = "Target"
+)
+
 // SuppressionPolicySpec Defines a policy to apply to alerts to suppress duplicates
 type SuppressionPolicySpec struct {
 	// Defines the duration of the suppression.
 	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Defines if duplicates are identified by the event or by the rendered targets of the actions.
+	// +kubebuilder:default=Event
+	// +kubebuilder:validation:Optional
+	Mode SuppressionMode `json:"mode,omitempty"`
+	// Defines the event labels that identify duplicate events, by default all the labels are used.
+	// +kubebuilder:validation:Optional
+	GroupBy []string `json:"groupBy,omitempty"`
+	// Defines a template rendered with the event that identifies duplicate events, for
+	// example '{{ .Data.namespace }}/{{ .Data.deployment }}'.
+	// +kubebuilder:validation:Optional
+	GroupByTemplate string `json:"groupByTemplate,omitempty"`
 }
 
 // PrometheusAlertSpec definition of a monitored prometheus alert
@@ -101,6 +135,87 @@ type RestartSpec struct {
 	DeploymentRef DeploymentReference `json:"deploymentRef"`
 }
 
+// ObjectSelector selects all objects of a kind in a namespace matching a label selector
+type ObjectSelector struct {
+	// `namespace` is the namespace of the objects.
+	Namespace string `json:"namespace"`
+	// `kind` is the type of the objects
+	Kind string `json:"kind"`
+	// `apiVersion` is the version of the objects
+	ApiVersion string `json:"apiVersion"`
+	// `labelSelector` selects the objects by their labels
+	LabelSelector metav1.LabelSelector `json:"labelSelector"`
+}
+
+// ConditionMatch matches a status condition on an object, for example Available=True
+type ConditionMatch struct {
+	// `type` is the type of the status condition.
+	Type string `json:"type"`
+	// `status` is the expected status of the condition.
+	// +kubebuilder:default=True
+	Status metav1.ConditionStatus `json:"status,omitempty"`
+}
+
+// JSONPathMatch matches the result of a JSONPath expression against a value
+type JSONPathMatch struct {
+	// `expression` is a JSONPath expression, for example '{.status.readyReplicas}'.
+	Expression string `json:"expression"`
+	// `value` is the expected value of the evaluated expression.
+	Value string `json:"value"`
+}
+
+// WaitForSpec polls one or more objects until a condition holds or the timeout expires
+type WaitForSpec struct {
+	// +kubebuilder:validation:Optional
+	TargetObjectRef *ObjectReference `json:"targetObjectRef,omitempty"`
+	// +kubebuilder:validation:Optional
+	Selector *ObjectSelector `json:"selector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Condition *ConditionMatch `json:"condition,omitempty"`
+	// +kubebuilder:validation:Optional
+	JSONPath *JSONPathMatch `json:"jsonPath,omitempty"`
+
+	// Defines how long to wait for the condition before failing the action.
+	Timeout metav1.Duration `json:"timeout"`
+	// Defines how often the objects are polled, defaults to 5 seconds.
+	// +kubebuilder:validation:Optional
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+}
+
+// TargetRef returns a reference to the object the action acts on, or nil if the action doesn't change any object.
+// The name and namespace of the reference may be templates.
+func (a *Action) TargetRef() *ObjectReference {
+	switch {
+	case a.Delete != nil:
+		return &a.Delete.TargetObjectRef
+	case a.Patch != nil:
+		return &a.Patch.TargetObjectRef
+	case a.Restart != nil:
+		ref := a.Restart.DeploymentRef
+		return &ObjectReference{Namespace: ref.Namespace, Name: ref.Name, Kind: "Deployment", ApiVersion: "apps/v1"}
+	case a.Debug != nil:
+		ref := a.Debug.PodRef
+		return &ObjectReference{Namespace: ref.Namespace, Name: ref.Name, Kind: "Pod", ApiVersion: "v1"}
+	}
+	return nil
+}
+
+// UndoAction returns the undo of the action as an action of its own, or nil if the action has
+// no undo or the undo is inferred, in which case it targets the object of the action.
+func (a *Action) UndoAction() *Action {
+	if a.Undo == nil || (a.Undo.Delete == nil && a.Undo.Patch == nil) {
+		return nil
+	}
+
+	return &Action{
+		Name:         a.Name + "-undo",
+		RetryEnabled: a.RetryEnabled,
+		Delete:       a.Undo.Delete,
+		Patch:        a.Undo.Patch,
+	}
+}
+
 func (o *ObjectReference) ToGroupVersionKind() (schema.GroupVersionKind, error) {
 	gv, err := schema.ParseGroupVersion(o.ApiVersion)
 	if err != nil {
@@ -110,6 +225,95 @@ func (o *ObjectReference) ToGroupVersionKind() (schema.GroupVersionKind, error)
 	return gv.WithKind(o.Kind), nil
 }
 
+// UndoSpec defines how and when to revert an action. When no undo action is defined the
+// undo is inferred from the action, which is currently supported for patch actions.
+type UndoSpec struct {
+	// Defines how long after the action is taken to revert it.
+	// +kubebuilder:validation:Optional
+	RevertAfter *metav1.Duration `json:"revertAfter,omitempty"`
+	// Revert the action once the triggering event is no longer active.
+	// +kubebuilder:validation:Optional
+	OnResolve bool `json:"onResolve,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Delete *DeleteSpec `json:"delete,omitempty"`
+	// +kubebuilder:validation:Optional
+	Patch *PatchSpec `json:"patch,omitempty"`
+}
+
+// VerifySpec defines how to verify that the actions resolved the triggering event. Only the Prometheus,
+// Alertmanager, PodStatus and NodeCondition event sources support verification, the verification of
+// the events of other sources, or injected by a CounterMeasureTrigger, is skipped.
+type VerifySpec struct {
+	// Defines how long to wait after the actions complete before asking the
+	// event source if the triggering event is still active.
+	GracePeriod metav1.Duration `json:"gracePeriod"`
+}
+
+// ApprovalSpec requires the actions to be approved before they are executed
+type ApprovalSpec struct {
+	// Defines how long a run waits to be approved before it expires.
+	Timeout metav1.Duration `json:"timeout"`
+}
+
+// GuardSpec defines the checks made before an action that removes, restarts or patches pods is taken
+type GuardSpec struct {
+	// Defines the percentage of the workload's pods that must remain Ready after the action.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Optional
+	MinReadyPercent *int32 `json:"minReadyPercent,omitempty"`
+}
+
+// TimeWindow a recurring window of time
+type TimeWindow struct {
+	// `start` is a cron expression for when the window starts, for example '0 22 * * 1-5'.
+	Start string `json:"start"`
+	// `duration` is how long the window lasts after it starts.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// OutsideScheduleAction defines what happens to events outside of the schedule
+// +kubebuilder:validation:Enum=Skip;DryRun
+type OutsideScheduleAction string
+
+const (
+	OutsideScheduleSkip   OutsideScheduleAction = "Skip"
+	OutsideScheduleDryRun OutsideScheduleAction = "DryRun"
+)
+
+// ScheduleSpec defines when a countermeasure is allowed to act
+type ScheduleSpec struct {
+	// `timezone` is the IANA name of the timezone of the windows, for example 'America/New_York',
+	// defaults to UTC.
+	// +kubebuilder:validation:Optional
+	Timezone string `json:"timezone,omitempty"`
+	// The windows in which the countermeasure is active, when none are defined it's always active.
+	// +kubebuilder:validation:Optional
+	ActiveWindows []TimeWindow `json:"activeWindows,omitempty"`
+	// The windows in which the countermeasure is never active, even inside an active window.
+	// +kubebuilder:validation:Optional
+	BlackoutWindows []TimeWindow `json:"blackoutWindows,omitempty"`
+	// Defines if events outside of the schedule are skipped or acted on as a dry run.
+	// +kubebuilder:default=Skip
+	// +kubebuilder:validation:Optional
+	OutsideSchedule OutsideScheduleAction `json:"outsideSchedule,omitempty"`
+}
+
+// CircuitBreakerSpec stops a countermeasure from executing after repeated failures
+type CircuitBreakerSpec struct {
+	// Defines how many consecutive failed or ineffective executions within the window trip the
+	// breaker, a successful execution clears the failures counted so far.
+	// +kubebuilder:validation:Minimum=1
+	MaxFailures int32 `json:"maxFailures"`
+	// Defines the sliding time window the failures are counted in.
+	Window metav1.Duration `json:"window"`
+	// Defines how long after tripping the breaker is reset automatically, when not set the
+	// breaker can only be reset with the reset annotation.
+	// +kubebuilder:validation:Optional
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
 // Action defines an action to be taken when the event source detects a condition that needs attention.
 type Action struct {
 	Name string `json:"name"`
@@ -130,6 +334,17 @@ type Action struct {
 	Debug *DebugSpec `json:"debug,omitempty"`
 	// +kubebuilder:validation:Optional
 	Restart *RestartSpec `json:"restart,omitempty"`
+	// +kubebuilder:validation:Optional
+	WaitFor *WaitForSpec `json:"waitFor,omitempty"`
+
+	// Defines how to compensate for this action after a period of time or once the event resolves.
+	// +kubebuilder:validation:Optional
+	Undo *UndoSpec `json:"undo,omitempty"`
+
+	// Defines the checks made before delete, restart and patch actions are taken. Objects labeled
+	// with countermeasures/protected=true are never deleted, restarted, patched or debugged.
+	// +kubebuilder:validation:Optional
+	Guard *GuardSpec `json:"guard,omitempty"`
 }
 
 // CounterMeasureSpec defines the desired state of CounterMeasure
@@ -138,9 +353,41 @@ type CounterMeasureSpec struct {
 	// Important: Run "make" to regenerate code after modifying this file
 
 	OnEvent OnEventSpec `json:"onEvent"`
-	Actions []Action    `json:"actions"`
+	// +kubebuilder:validation:Optional
+	Actions []Action `json:"actions,omitempty"`
+	// Defines a CounterMeasureTemplate the actions are expanded from, instead of listing the actions.
+	// +kubebuilder:validation:Optional
+	Template *TemplateReference `json:"template,omitempty"`
 	// +kubebuilder:default=false
 	DryRun bool `json:"dryRun,omitempty"`
+
+	// Defines an optional check that the triggering event is no longer active after the actions complete.
+	// +kubebuilder:validation:Optional
+	Verify *VerifySpec `json:"verify,omitempty"`
+
+	// Defines that a matching event creates a CounterMeasureRun pending approval instead
+	// of executing the actions.
+	// +kubebuilder:validation:Optional
+	Approval *ApprovalSpec `json:"approval,omitempty"`
+
+	// Defines how many finished CounterMeasureRuns are kept for this countermeasure.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	RunHistoryLimit *int32 `json:"runHistoryLimit,omitempty"`
+
+	// Defines a circuit breaker that stops the countermeasure after repeated failures.
+	// +kubebuilder:validation:Optional
+	CircuitBreaker *CircuitBreakerSpec `json:"circuitBreaker,omitempty"`
+
+	// Defines when the countermeasure is allowed to act.
+	// +kubebuilder:validation:Optional
+	Schedule *ScheduleSpec `json:"schedule,omitempty"`
+
+	// Name of a ServiceAccount in the namespace of the countermeasure the actions are taken as,
+	// so they're authorized by the RBAC of that ServiceAccount instead of the operator's.
+	// +kubebuilder:validation:Optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 }
 
 // CounterMeasureStatus defines the observed state of CounterMeasure
@@ -151,9 +398,59 @@ type CounterMeasureStatus struct {
 	LastStatus           StatusType   `json:"lastStatus,omitempty"`
 	LastStatusChangeTime *metav1.Time `json:"lastStatusChangeTime,omitempty"`
 
+	LastVerification     VerificationType `json:"lastVerification,omitempty"`
+	LastVerificationTime *metav1.Time     `json:"lastVerificationTime,omitempty"`
+
+	// Actions that have been taken and are waiting to be reverted.
+	PendingReverts []PendingRevert `json:"pendingReverts,omitempty"`
+
+	// Events that are suppressed by the suppression policy, kept so the suppression
+	// survives operator restarts.
+	Suppressions []Suppression `json:"suppressions,omitempty"`
+
+	// Times of the consecutive failed or ineffective executions counted by the circuit breaker.
+	RecentFailures []metav1.Time `json:"recentFailures,omitempty"`
+
 	Conditions []metav1.Condition `json:"conditions"`
 }
 
+// EventRecord is a copy of an event that triggered a countermeasure
+type EventRecord struct {
+	Name       string            `json:"name"`
+	ActiveTime *metav1.Time      `json:"activeTime,omitempty"`
+	Data       map[string]string `json:"data,omitempty"`
+	// `source` is the namespace/name of the event source that produced the event.
+	Source string `json:"source,omitempty"`
+	// `sourceKind` is the kind of the event source that produced the event.
+	SourceKind string `json:"sourceKind,omitempty"`
+}
+
+// Suppression an event that won't trigger the countermeasure again until the deadline
+type Suppression struct {
+	// `eventKey` is the key of the event, a hash of the event name and data.
+	EventKey string      `json:"eventKey"`
+	Until    metav1.Time `json:"until"`
+}
+
+// InferredPatch is a merge patch that restores a patched object to its original state
+type InferredPatch struct {
+	TargetObjectRef ObjectReference `json:"targetObjectRef"`
+	Patch           string          `json:"patch"`
+}
+
+// PendingRevert an action that was taken and has not been reverted yet
+type PendingRevert struct {
+	// `action` is the name of the action to revert.
+	Action string      `json:"action"`
+	Event  EventRecord `json:"event"`
+
+	RevertAt  *metav1.Time `json:"revertAt,omitempty"`
+	OnResolve bool         `json:"onResolve,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	InferredPatch *InferredPatch `json:"inferredPatch,omitempty"`
+}
+
 type StatusType string
 
 const (
@@ -163,6 +460,13 @@ const (
 	Unknown    StatusType = "Unknown"
 )
 
+type VerificationType string
+
+const (
+	Resolved    VerificationType = "Resolved"
+	Ineffective VerificationType = "Ineffective"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 
@@ -170,6 +474,7 @@ const (
 // +kubebuilder:printcolumn:name="Dry Run",type=boolean,JSONPath=`.spec.dryRun`
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.lastStatus`
 // +kubebuilder:printcolumn:name="Status Last Changed",type=string,JSONPath=`.status.lastStatusChangeTime`
+// +kubebuilder:printcolumn:name="Last Verification",type=string,JSONPath=`.status.lastVerification`,priority=1
 // +kubebuilder:resource:shortName=ctm
 // +kubebuilder:singular=countermeasure
 type CounterMeasure struct {
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -156,5 +157,38 @@ var _ = Describe("CounterMeasures webhook", func() {
 			Expect(err).Should(HaveOccurred())
 			Ω(err.Error()).Should(Equal("admission webhook \"vcountermeasure.kb.io\" denied the request: event name is required"))
 		})
+
+		It("should fail if a waitFor action has no target", func() {
+			counterMeasure := &CounterMeasure{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "countermeasure.vilaverde.rocks/v1alpha1",
+					Kind:       "CounterMeasure",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      CounterMeasureName,
+					Namespace: CounterMeasureNamespace,
+				},
+				Spec: CounterMeasureSpec{
+					OnEvent: OnEventSpec{
+						EventName: "CPUThrottlingHigh",
+					},
+					Actions: []Action{
+						{
+							Name: "wait",
+							WaitFor: &WaitForSpec{
+								Condition: &ConditionMatch{
+									Type: "Available",
+								},
+								Timeout: metav1.Duration{Duration: time.Minute},
+							},
+						},
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, counterMeasure)
+			Expect(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(Equal("admission webhook \"vcountermeasure.kb.io\" denied the request: waitFor config for action 'wait' requires exactly one of targetObjectRef or selector"))
+		})
 	})
 })
@@ -0,0 +1,64 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CounterMeasureNamespaceGrantSpec defines the namespaces whose countermeasures may target objects
+// in the namespace of the grant.
+type CounterMeasureNamespaceGrantSpec struct {
+	// Namespaces of the countermeasures granted access, '*' grants every namespace.
+	// +kubebuilder:validation:MinItems=1
+	FromNamespaces []string `json:"fromNamespaces"`
+}
+
+// Grants checks if countermeasures in the namespace may target objects in the namespace of the grant.
+func (g *CounterMeasureNamespaceGrant) Grants(namespace string) bool {
+	for _, from := range g.Spec.FromNamespaces {
+		if from == "*" || from == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+//+kubebuilder:object:root=true
+
+// CounterMeasureNamespaceGrant is the Schema for the countermeasurenamespacegrants API
+// +kubebuilder:resource:shortName=ctmgrant
+// +kubebuilder:printcolumn:name="From",type=string,JSONPath=`.spec.fromNamespaces`
+type CounterMeasureNamespaceGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CounterMeasureNamespaceGrantSpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// CounterMeasureNamespaceGrantList contains a list of CounterMeasureNamespaceGrant
+type CounterMeasureNamespaceGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CounterMeasureNamespaceGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CounterMeasureNamespaceGrant{}, &CounterMeasureNamespaceGrantList{})
+}
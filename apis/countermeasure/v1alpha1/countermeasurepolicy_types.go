@@ -0,0 +1,130 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ActionRateLimit caps how many actions of a type can be taken in a time window
+type ActionRateLimit struct {
+	// `actionType` is the type of action to limit, for example restart or delete.
+	ActionType string `json:"actionType"`
+	// Defines the sliding time window the actions are counted in.
+	Window metav1.Duration `json:"window"`
+
+	// Defines how many actions of this type can be taken across the cluster in the window.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	ClusterLimit *int32 `json:"clusterLimit,omitempty"`
+	// Defines how many actions of this type can be taken in each namespace in the window.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	NamespaceLimit *int32 `json:"namespaceLimit,omitempty"`
+}
+
+// KindReference references a kind of object in an API group
+type KindReference struct {
+	// `group` is the API group of the kind, empty for the core group.
+	Group string `json:"group,omitempty"`
+	// `kind` is the type of object, '*' matches every kind in the group.
+	Kind string `json:"kind"`
+}
+
+// TargetPolicy defines the namespaces and kinds of objects countermeasures may act on
+type TargetPolicy struct {
+	// The namespaces that may be acted on, when empty all namespaces are allowed.
+	// +kubebuilder:validation:Optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// The namespaces that may never be acted on.
+	// +kubebuilder:validation:Optional
+	DeniedNamespaces []string `json:"deniedNamespaces,omitempty"`
+	// The kinds that may be acted on, when empty all kinds are allowed.
+	// +kubebuilder:validation:Optional
+	AllowedKinds []KindReference `json:"allowedKinds,omitempty"`
+	// The kinds that may never be acted on.
+	// +kubebuilder:validation:Optional
+	DeniedKinds []KindReference `json:"deniedKinds,omitempty"`
+}
+
+// CounterMeasurePolicySpec defines the operator wide policies applied to all countermeasures
+type CounterMeasurePolicySpec struct {
+	// +kubebuilder:validation:Optional
+	RateLimits []ActionRateLimit `json:"rateLimits,omitempty"`
+	// +kubebuilder:validation:Optional
+	Targets *TargetPolicy `json:"targets,omitempty"`
+}
+
+// Allows returns an error if the policy doesn't allow acting on objects of the kind in the namespace.
+func (p *TargetPolicy) Allows(namespace string, gk schema.GroupKind) error {
+	if contains(p.DeniedNamespaces, namespace) ||
+		(len(p.AllowedNamespaces) > 0 && !contains(p.AllowedNamespaces, namespace)) {
+		return fmt.Errorf("namespace '%s' is not allowed by the countermeasure policy", namespace)
+	}
+
+	if matchesKind(p.DeniedKinds, gk) || (len(p.AllowedKinds) > 0 && !matchesKind(p.AllowedKinds, gk)) {
+		return fmt.Errorf("kind '%s' is not allowed by the countermeasure policy", gk.String())
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesKind(kinds []KindReference, gk schema.GroupKind) bool {
+	for _, k := range kinds {
+		if k.Group == gk.Group && (k.Kind == "*" || k.Kind == gk.Kind) {
+			return true
+		}
+	}
+	return false
+}
+
+//+kubebuilder:object:root=true
+
+// CounterMeasurePolicy is the Schema for the countermeasurepolicies API
+// +kubebuilder:resource:scope=Cluster,shortName=ctmpolicy
+// +kubebuilder:singular=countermeasurepolicy
+type CounterMeasurePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CounterMeasurePolicySpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// CounterMeasurePolicyList contains a list of CounterMeasurePolicy
+type CounterMeasurePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CounterMeasurePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CounterMeasurePolicy{}, &CounterMeasurePolicyList{})
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ApprovedAnnotation can be set to "true" on a CounterMeasureRun as an alternative to spec.approved
+	ApprovedAnnotation = "countermeasure.vilaverde.rocks/approved"
+	// CounterMeasureLabel is the name of the CounterMeasure that created a CounterMeasureRun
+	CounterMeasureLabel = "countermeasure.vilaverde.rocks/countermeasure"
+	// EventKeyLabel is the key of the event that triggered a CounterMeasureRun
+	EventKeyLabel = "countermeasure.vilaverde.rocks/event-key"
+)
+
+// PlannedAction an action that will be taken by a run, with the target rendered from the event.
+type PlannedAction struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Target string `json:"target"`
+}
+
+// CounterMeasureRunSpec defines an execution of a CounterMeasure for an event
+type CounterMeasureRunSpec struct {
+	// `counterMeasure` is the name of the CounterMeasure in the same namespace.
+	CounterMeasure string `json:"counterMeasure"`
+	// `clusterScoped` is set when `counterMeasure` is the name of a ClusterCounterMeasure.
+	ClusterScoped bool        `json:"clusterScoped,omitempty"`
+	Event         EventRecord `json:"event"`
+	DryRun        bool        `json:"dryRun,omitempty"`
+
+	PlannedActions []PlannedAction `json:"plannedActions,omitempty"`
+	// `actions` are the actions of the countermeasure, expanded from its template if any, when the
+	// run was created. An approved run is only executed while the countermeasure has the same actions.
+	// +kubebuilder:validation:Optional
+	Actions []Action `json:"actions,omitempty"`
+
+	// Set to true to approve a run that is pending approval.
+	// +kubebuilder:validation:Optional
+	Approved bool `json:"approved,omitempty"`
+	// Defines when a run that is pending approval expires.
+	// +kubebuilder:validation:Optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+type RunPhase string
+
+const (
+	PendingApproval RunPhase = "PendingApproval"
+	Running         RunPhase = "Running"
+	Succeeded       RunPhase = "Succeeded"
+	Failed          RunPhase = "Failed"
+	Expired         RunPhase = "Expired"
+	Skipped         RunPhase = "Skipped"
+)
+
+type ActionOutcome string
+
+const (
+	ActionSucceeded ActionOutcome = "Succeeded"
+	ActionFailed    ActionOutcome = "Failed"
+)
+
+// ActionResult records the execution of a single action in a run
+type ActionResult struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// `target` is the name of the object the action was taken on, rendered from the event.
+	Target    string        `json:"target,omitempty"`
+	StartTime *metav1.Time  `json:"startTime,omitempty"`
+	EndTime   *metav1.Time  `json:"endTime,omitempty"`
+	Outcome   ActionOutcome `json:"outcome,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// CounterMeasureRunStatus defines the observed state of CounterMeasureRun
+type CounterMeasureRunStatus struct {
+	Phase          RunPhase     `json:"phase,omitempty"`
+	Message        string       `json:"message,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// The actions that have been executed, in the order they were taken.
+	Actions []ActionResult `json:"actions,omitempty"`
+}
+
+// IsFinished checks if the run has reached a terminal phase.
+func (s *CounterMeasureRunStatus) IsFinished() bool {
+	return s.Phase == Succeeded || s.Phase == Failed || s.Phase == Expired || s.Phase == Skipped
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// CounterMeasureRun is the Schema for the countermeasureruns API
+// +kubebuilder:printcolumn:name="CounterMeasure",type=string,JSONPath=`.spec.counterMeasure`
+// +kubebuilder:printcolumn:name="Event",type=string,JSONPath=`.spec.event.name`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Dry Run",type=boolean,JSONPath=`.spec.dryRun`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:shortName=ctmrun
+// +kubebuilder:singular=countermeasurerun
+type CounterMeasureRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CounterMeasureRunSpec   `json:"spec"`
+	Status CounterMeasureRunStatus `json:"status,omitempty"`
+}
+
+// IsApproved checks if the run was approved by either the spec or the annotation.
+func (r *CounterMeasureRun) IsApproved() bool {
+	return r.Spec.Approved || r.Annotations[ApprovedAnnotation] == "true"
+}
+
+//+kubebuilder:object:root=true
+
+// CounterMeasureRunList contains a list of CounterMeasureRun
+type CounterMeasureRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CounterMeasureRun `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CounterMeasureRun{}, &CounterMeasureRunList{})
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// parameterRef matches the references to the template parameters, i.e. $(params.deployment)
+var parameterRef = regexp.MustCompile(`\$\(params\.([^)]*)\)`)
+
+// TemplateParameter a parameter of a CounterMeasureTemplate
+type TemplateParameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Defines the value used when the countermeasure doesn't set the parameter, the parameter
+	// is required when there is no default.
+	// +kubebuilder:validation:Optional
+	Default *string `json:"default,omitempty"`
+}
+
+// CounterMeasureTemplateSpec defines a list of actions parameterized with '$(params.<name>)' references,
+// which are replaced before the event templates of the actions are rendered.
+type CounterMeasureTemplateSpec struct {
+	// +kubebuilder:validation:Optional
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+	// +kubebuilder:validation:MinItems=1
+	Actions []Action `json:"actions"`
+}
+
+// TemplateReference references the CounterMeasureTemplate the actions of a countermeasure are expanded from
+type TemplateReference struct {
+	Name string `json:"name"`
+	// `namespace` of the template, only used by cluster countermeasures, a CounterMeasure uses the
+	// templates in its own namespace.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// +kubebuilder:validation:Optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// Expand replaces the parameter references in the actions of the template with the values,
+// falling back to the parameter defaults.
+func (t *CounterMeasureTemplate) Expand(values map[string]string) ([]Action, error) {
+	resolved := make(map[string]string, len(t.Spec.Parameters))
+	missing := make([]string, 0)
+	for _, param := range t.Spec.Parameters {
+		if value, ok := values[param.Name]; ok {
+			resolved[param.Name] = value
+		} else if param.Default != nil {
+			resolved[param.Name] = *param.Default
+		} else {
+			missing = append(missing, param.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template '%s' requires the parameters: %s", t.Name, strings.Join(missing, ", "))
+	}
+
+	unknown := make([]string, 0)
+	for name := range values {
+		if _, ok := resolved[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("template '%s' has no parameters: %s", t.Name, strings.Join(unknown, ", "))
+	}
+
+	raw, err := json.Marshal(t.Spec.Actions)
+	if err != nil {
+		return nil, err
+	}
+
+	var undefined []string
+	expanded := parameterRef.ReplaceAllFunc(raw, func(ref []byte) []byte {
+		name := string(parameterRef.FindSubmatch(ref)[1])
+		value, ok := resolved[name]
+		if !ok {
+			undefined = append(undefined, name)
+			return ref
+		}
+
+		// the references are inside of json strings so the value has to be escaped
+		quoted, _ := json.Marshal(value)
+		return quoted[1 : len(quoted)-1]
+	})
+
+	if len(undefined) > 0 {
+		return nil, fmt.Errorf("template '%s' references undefined parameters: %s", t.Name, strings.Join(undefined, ", "))
+	}
+
+	actions := make([]Action, 0, len(t.Spec.Actions))
+	if err := json.Unmarshal(expanded, &actions); err != nil {
+		return nil, err
+	}
+
+	return actions, nil
+}
+
+//+kubebuilder:object:root=true
+
+// CounterMeasureTemplate is the Schema for the countermeasuretemplates API
+// +kubebuilder:resource:shortName=ctmtemplate
+// +kubebuilder:singular=countermeasuretemplate
+type CounterMeasureTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CounterMeasureTemplateSpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// CounterMeasureTemplateList contains a list of CounterMeasureTemplate
+type CounterMeasureTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CounterMeasureTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CounterMeasureTemplate{}, &CounterMeasureTemplateList{})
+}
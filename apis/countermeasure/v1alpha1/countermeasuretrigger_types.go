@@ -0,0 +1,105 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CounterMeasureTriggerSpec defines an event injected by hand, to test a countermeasure end-to-end
+// or to run it during an incident. The event is subject to the circuit breaker, schedule and approval
+// of the countermeasure like any other event. The event of every trigger has a unique key, so only a
+// suppression policy in the Target mode, or a countermeasure that is still running, suppresses it.
+type CounterMeasureTriggerSpec struct {
+	// `counterMeasure` is the name of a CounterMeasure in the same namespace, the event is only
+	// delivered to it. When not set the event is delivered to the CounterMeasures in the same
+	// namespace listening for `eventName`, never to the countermeasures of other namespaces or
+	// to ClusterCounterMeasures.
+	// +optional
+	CounterMeasure string `json:"counterMeasure,omitempty"`
+	// `eventName` the name of the event, defaults to the name of the event the countermeasure listens for.
+	// +optional
+	EventName string `json:"eventName,omitempty"`
+	// `data` of the event, the trigger key is always set to the name of the trigger.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+}
+
+type TriggerPhase string
+
+const (
+	TriggerInjected TriggerPhase = "Injected"
+	TriggerFailed   TriggerPhase = "Failed"
+)
+
+// TriggeredRun a CounterMeasureRun created for the event of the trigger
+type TriggeredRun struct {
+	Name           string   `json:"name"`
+	CounterMeasure string   `json:"counterMeasure"`
+	Phase          RunPhase `json:"phase,omitempty"`
+}
+
+// CounterMeasureTriggerStatus defines the observed state of CounterMeasureTrigger
+type CounterMeasureTriggerStatus struct {
+	Phase   TriggerPhase `json:"phase,omitempty"`
+	Message string       `json:"message,omitempty"`
+	// `eventName` the name of the injected event.
+	EventName string `json:"eventName,omitempty"`
+	// `eventKey` the key of the injected event, the runs created for it are labeled with it.
+	EventKey      string       `json:"eventKey,omitempty"`
+	InjectionTime *metav1.Time `json:"injectionTime,omitempty"`
+
+	// The runs created for the event in the namespace of the trigger.
+	Runs []TriggeredRun `json:"runs,omitempty"`
+}
+
+// IsInjected checks if the event of the trigger was already injected, a trigger injects its event
+// only once.
+func (s *CounterMeasureTriggerStatus) IsInjected() bool {
+	return s.InjectionTime != nil
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// CounterMeasureTrigger is the Schema for the countermeasuretriggers API
+// +kubebuilder:printcolumn:name="CounterMeasure",type=string,JSONPath=`.spec.counterMeasure`
+// +kubebuilder:printcolumn:name="Event",type=string,JSONPath=`.status.eventName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:shortName=ctmtrigger
+// +kubebuilder:singular=countermeasuretrigger
+type CounterMeasureTrigger struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CounterMeasureTriggerSpec   `json:"spec"`
+	Status CounterMeasureTriggerStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CounterMeasureTriggerList contains a list of CounterMeasureTrigger
+type CounterMeasureTriggerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CounterMeasureTrigger `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CounterMeasureTrigger{}, &CounterMeasureTriggerList{})
+}
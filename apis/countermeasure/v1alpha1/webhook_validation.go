@@ -1,9 +1,15 @@
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"text/template"
+	"time"
 
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	util "k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -19,18 +25,155 @@ func ValidateSpec(spec *CounterMeasureSpec) error {
 	}
 
 	if len(spec.Actions) != 0 {
+		if spec.Template != nil {
+			validationErrors = append(validationErrors, fmt.Errorf("actions and template are mutually exclusive"))
+		}
+
 		for _, action := range spec.Actions {
 			if err := ValidateAction(action); err != nil {
 				validationErrors = append(validationErrors, err)
 			}
 		}
-	} else {
+	} else if spec.Template == nil {
 		validationErrors = append(validationErrors, fmt.Errorf("one or more actions are required"))
 	}
 
+	if spec.Verify != nil && spec.Verify.GracePeriod.Duration < 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("verify grace period must not be negative"))
+	}
+
+	if spec.Approval != nil && spec.Approval.Timeout.Duration <= 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("approval requires a positive timeout"))
+	}
+
+	if spec.CircuitBreaker != nil {
+		if err := ValidateCircuitBreaker(spec.CircuitBreaker); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
+
+	if spec.Schedule != nil {
+		if err := ValidateSchedule(spec.Schedule); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
+
+	if WebhookClient != nil {
+		if err := ValidateTargetPolicies(context.Background(), WebhookClient, spec.Actions); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
+
+	return util.NewAggregate(validationErrors)
+}
+
+// ValidateClusterSpec validates the spec of a ClusterCounterMeasure, which has no namespace to find
+// a ServiceAccount in.
+func ValidateClusterSpec(spec *ClusterCounterMeasureSpec) error {
+	validationErrors := make([]error, 0)
+
+	if err := ValidateSpec(&spec.CounterMeasureSpec); err != nil {
+		validationErrors = append(validationErrors, err)
+	}
+
+	if spec.ServiceAccountName != "" {
+		validationErrors = append(validationErrors, fmt.Errorf("serviceAccountName is not supported by cluster countermeasures"))
+	}
+
+	if spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector); err != nil {
+			validationErrors = append(validationErrors, fmt.Errorf("invalid namespaceSelector: %w", err))
+		}
+	}
+
 	return util.NewAggregate(validationErrors)
 }
 
+// ValidateTargetPolicies check the targets of the actions and their undo actions are allowed by the
+// CounterMeasurePolicies, targets with a templated namespace are checked once the template is rendered
+// when the action is taken.
+func ValidateTargetPolicies(ctx context.Context, c client.Reader, actions []Action) error {
+	policies := &CounterMeasurePolicyList{}
+	if err := c.List(ctx, policies); err != nil {
+		return err
+	}
+
+	checked := make([]Action, 0, len(actions))
+	for _, action := range actions {
+		checked = append(checked, action)
+		if undo := action.UndoAction(); undo != nil {
+			checked = append(checked, *undo)
+		}
+	}
+
+	policyErrors := make([]error, 0)
+	for _, action := range checked {
+		target := action.TargetRef()
+		if target == nil || strings.Contains(target.Namespace, "{{") {
+			continue
+		}
+
+		gvk, err := target.ToGroupVersionKind()
+		if err != nil {
+			policyErrors = append(policyErrors, err)
+			continue
+		}
+
+		for _, policy := range policies.Items {
+			if policy.Spec.Targets == nil {
+				continue
+			}
+
+			if err := policy.Spec.Targets.Allows(target.Namespace, gvk.GroupKind()); err != nil {
+				policyErrors = append(policyErrors, fmt.Errorf("action '%s': %w", action.Name, err))
+			}
+		}
+	}
+
+	return util.NewAggregate(policyErrors)
+}
+
+func ValidateSchedule(s *ScheduleSpec) error {
+	scheduleErrors := make([]error, 0)
+
+	if len(s.Timezone) > 0 {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			scheduleErrors = append(scheduleErrors, fmt.Errorf("schedule timezone '%s' is invalid: %w", s.Timezone, err))
+		}
+	}
+
+	windows := append(append([]TimeWindow{}, s.ActiveWindows...), s.BlackoutWindows...)
+	for _, window := range windows {
+		if _, err := cron.ParseStandard(window.Start); err != nil {
+			scheduleErrors = append(scheduleErrors, fmt.Errorf("schedule window start '%s' is invalid: %w", window.Start, err))
+		}
+
+		if window.Duration.Duration <= 0 {
+			scheduleErrors = append(scheduleErrors, fmt.Errorf("schedule window '%s' requires a positive duration", window.Start))
+		}
+	}
+
+	return util.NewAggregate(scheduleErrors)
+}
+
+func ValidateCircuitBreaker(b *CircuitBreakerSpec) error {
+	breakerErrors := make([]error, 0)
+
+	if b.MaxFailures < 1 {
+		breakerErrors = append(breakerErrors, fmt.Errorf("circuit breaker maxFailures must be at least 1"))
+	}
+
+	if b.Window.Duration <= 0 {
+		breakerErrors = append(breakerErrors, fmt.Errorf("circuit breaker requires a positive window"))
+	}
+
+	if b.Cooldown != nil && b.Cooldown.Duration < 0 {
+		breakerErrors = append(breakerErrors, fmt.Errorf("circuit breaker cooldown must not be negative"))
+	}
+
+	return util.NewAggregate(breakerErrors)
+}
+
 func ValidateAction(a Action) error {
 
 	var (
@@ -45,10 +188,27 @@ func ValidateAction(a Action) error {
 		}
 	}
 
+	if a.WaitFor != nil {
+		if err := ValidateWaitFor(a.Name, a.WaitFor); err != nil {
+			actionErrors = append(actionErrors, err)
+		}
+	}
+
+	if a.Undo != nil {
+		if err := ValidateUndo(a); err != nil {
+			actionErrors = append(actionErrors, err)
+		}
+	}
+
 	// checks to see that the action only contains 1 type of action
 	tt := reflect.ValueOf(a)
 	for i := 0; i < tt.NumField(); i++ {
 		f := tt.Field(i)
+		// the undo and guard specs are not action types
+		if name := tt.Type().Field(i).Name; name == "Undo" || name == "Guard" {
+			continue
+		}
+
 		if f.Type().Kind() == reflect.Pointer {
 			// we're only counting the pointers to the action type structs
 			if !f.IsNil() {
@@ -68,11 +228,76 @@ func ValidateAction(a Action) error {
 	return util.NewAggregate(actionErrors)
 }
 
+func ValidateWaitFor(name string, w *WaitForSpec) error {
+	waitErrors := make([]error, 0)
+
+	if (w.TargetObjectRef == nil) == (w.Selector == nil) {
+		waitErrors = append(waitErrors,
+			fmt.Errorf("waitFor config for action '%s' requires exactly one of targetObjectRef or selector", name))
+	}
+
+	if (w.Condition == nil) == (w.JSONPath == nil) {
+		waitErrors = append(waitErrors,
+			fmt.Errorf("waitFor config for action '%s' requires exactly one of condition or jsonPath", name))
+	}
+
+	if w.Timeout.Duration <= 0 {
+		waitErrors = append(waitErrors,
+			fmt.Errorf("waitFor config for action '%s' requires a positive timeout", name))
+	}
+
+	return util.NewAggregate(waitErrors)
+}
+
+func ValidateUndo(a Action) error {
+	undoErrors := make([]error, 0)
+	undo := a.Undo
+
+	if undo.RevertAfter == nil && !undo.OnResolve {
+		undoErrors = append(undoErrors,
+			fmt.Errorf("undo config for action '%s' requires revertAfter or onResolve", a.Name))
+	}
+
+	if undo.Delete != nil && undo.Patch != nil {
+		undoErrors = append(undoErrors,
+			fmt.Errorf("undo config for action '%s' should only have 1 defined action type", a.Name))
+	}
+
+	if undo.Delete == nil && undo.Patch == nil && a.Patch == nil {
+		undoErrors = append(undoErrors,
+			fmt.Errorf("undo for action '%s' can only be inferred for patch actions", a.Name))
+	}
+
+	return util.NewAggregate(undoErrors)
+}
+
 func ValidateOnEvent(e OnEventSpec) error {
 	// check the event name is present with a valid value
 	if len(e.EventName) == 0 {
 		return fmt.Errorf("event name is required")
 	}
 
+	if e.SuppressionPolicy != nil {
+		return ValidateSuppressionPolicy(e.SuppressionPolicy)
+	}
+
+	return nil
+}
+
+func ValidateSuppressionPolicy(p *SuppressionPolicySpec) error {
+	if len(p.GroupBy) > 0 && len(p.GroupByTemplate) > 0 {
+		return fmt.Errorf("suppression policy should only define one of groupBy or groupByTemplate")
+	}
+
+	if p.Mode == SuppressByTarget && (len(p.GroupBy) > 0 || len(p.GroupByTemplate) > 0) {
+		return fmt.Errorf("suppression policy in Target mode can't be grouped by the event")
+	}
+
+	if len(p.GroupByTemplate) > 0 {
+		if _, err := template.New("").Parse(p.GroupByTemplate); err != nil {
+			return fmt.Errorf("suppression policy groupByTemplate is invalid: %w", err)
+		}
+	}
+
 	return nil
 }
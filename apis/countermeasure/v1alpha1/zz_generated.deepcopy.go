@@ -48,6 +48,21 @@ func (in *Action) DeepCopyInto(out *Action) {
 		*out = new(RestartSpec)
 		**out = **in
 	}
+	if in.WaitFor != nil {
+		in, out := &in.WaitFor, &out.WaitFor
+		*out = new(WaitForSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Undo != nil {
+		in, out := &in.Undo, &out.Undo
+		*out = new(UndoSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Guard != nil {
+		in, out := &in.Guard, &out.Guard
+		*out = new(GuardSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Action.
@@ -61,7 +76,93 @@ func (in *Action) DeepCopy() *Action {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CounterMeasure) DeepCopyInto(out *CounterMeasure) {
+func (in *ActionRateLimit) DeepCopyInto(out *ActionRateLimit) {
+	*out = *in
+	out.Window = in.Window
+	if in.ClusterLimit != nil {
+		in, out := &in.ClusterLimit, &out.ClusterLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NamespaceLimit != nil {
+		in, out := &in.NamespaceLimit, &out.NamespaceLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionRateLimit.
+func (in *ActionRateLimit) DeepCopy() *ActionRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionResult) DeepCopyInto(out *ActionResult) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionResult.
+func (in *ActionResult) DeepCopy() *ActionResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalSpec) DeepCopyInto(out *ApprovalSpec) {
+	*out = *in
+	out.Timeout = in.Timeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalSpec.
+func (in *ApprovalSpec) DeepCopy() *ApprovalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreakerSpec) DeepCopyInto(out *CircuitBreakerSpec) {
+	*out = *in
+	out.Window = in.Window
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreakerSpec.
+func (in *CircuitBreakerSpec) DeepCopy() *CircuitBreakerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreakerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCounterMeasure) DeepCopyInto(out *ClusterCounterMeasure) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -69,18 +170,18 @@ func (in *CounterMeasure) DeepCopyInto(out *CounterMeasure) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasure.
-func (in *CounterMeasure) DeepCopy() *CounterMeasure {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCounterMeasure.
+func (in *ClusterCounterMeasure) DeepCopy() *ClusterCounterMeasure {
 	if in == nil {
 		return nil
 	}
-	out := new(CounterMeasure)
+	out := new(ClusterCounterMeasure)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CounterMeasure) DeepCopyObject() runtime.Object {
+func (in *ClusterCounterMeasure) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -88,31 +189,31 @@ func (in *CounterMeasure) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CounterMeasureList) DeepCopyInto(out *CounterMeasureList) {
+func (in *ClusterCounterMeasureList) DeepCopyInto(out *ClusterCounterMeasureList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CounterMeasure, len(*in))
+		*out = make([]ClusterCounterMeasure, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureList.
-func (in *CounterMeasureList) DeepCopy() *CounterMeasureList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCounterMeasureList.
+func (in *ClusterCounterMeasureList) DeepCopy() *ClusterCounterMeasureList {
 	if in == nil {
 		return nil
 	}
-	out := new(CounterMeasureList)
+	out := new(ClusterCounterMeasureList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CounterMeasureList) DeepCopyObject() runtime.Object {
+func (in *ClusterCounterMeasureList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -120,206 +221,1026 @@ func (in *CounterMeasureList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CounterMeasureSpec) DeepCopyInto(out *CounterMeasureSpec) {
+func (in *ClusterCounterMeasureSpec) DeepCopyInto(out *ClusterCounterMeasureSpec) {
 	*out = *in
-	in.OnEvent.DeepCopyInto(&out.OnEvent)
-	if in.Actions != nil {
-		in, out := &in.Actions, &out.Actions
-		*out = make([]Action, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.CounterMeasureSpec.DeepCopyInto(&out.CounterMeasureSpec)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureSpec.
-func (in *CounterMeasureSpec) DeepCopy() *CounterMeasureSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCounterMeasureSpec.
+func (in *ClusterCounterMeasureSpec) DeepCopy() *ClusterCounterMeasureSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CounterMeasureSpec)
+	out := new(ClusterCounterMeasureSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CounterMeasureStatus) DeepCopyInto(out *CounterMeasureStatus) {
+func (in *ConditionMatch) DeepCopyInto(out *ConditionMatch) {
 	*out = *in
-	if in.LastStatusChangeTime != nil {
-		in, out := &in.LastStatusChangeTime, &out.LastStatusChangeTime
-		*out = (*in).DeepCopy()
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureStatus.
-func (in *CounterMeasureStatus) DeepCopy() *CounterMeasureStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionMatch.
+func (in *ConditionMatch) DeepCopy() *ConditionMatch {
 	if in == nil {
 		return nil
 	}
-	out := new(CounterMeasureStatus)
+	out := new(ConditionMatch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DebugSpec) DeepCopyInto(out *DebugSpec) {
+func (in *CounterMeasure) DeepCopyInto(out *CounterMeasure) {
 	*out = *in
-	if in.Command != nil {
-		in, out := &in.Command, &out.Command
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	out.PodRef = in.PodRef
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DebugSpec.
-func (in *DebugSpec) DeepCopy() *DebugSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasure.
+func (in *CounterMeasure) DeepCopy() *CounterMeasure {
 	if in == nil {
 		return nil
 	}
-	out := new(DebugSpec)
+	out := new(CounterMeasure)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasure) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeleteSpec) DeepCopyInto(out *DeleteSpec) {
+func (in *CounterMeasureList) DeepCopyInto(out *CounterMeasureList) {
 	*out = *in
-	out.TargetObjectRef = in.TargetObjectRef
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CounterMeasure, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeleteSpec.
-func (in *DeleteSpec) DeepCopy() *DeleteSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureList.
+func (in *CounterMeasureList) DeepCopy() *CounterMeasureList {
 	if in == nil {
 		return nil
 	}
-	out := new(DeleteSpec)
+	out := new(CounterMeasureList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasureList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeploymentReference) DeepCopyInto(out *DeploymentReference) {
+func (in *CounterMeasureNamespaceGrant) DeepCopyInto(out *CounterMeasureNamespaceGrant) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentReference.
-func (in *DeploymentReference) DeepCopy() *DeploymentReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureNamespaceGrant.
+func (in *CounterMeasureNamespaceGrant) DeepCopy() *CounterMeasureNamespaceGrant {
 	if in == nil {
 		return nil
 	}
-	out := new(DeploymentReference)
+	out := new(CounterMeasureNamespaceGrant)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasureNamespaceGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+func (in *CounterMeasureNamespaceGrantList) DeepCopyInto(out *CounterMeasureNamespaceGrantList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CounterMeasureNamespaceGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
-func (in *ObjectReference) DeepCopy() *ObjectReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureNamespaceGrantList.
+func (in *CounterMeasureNamespaceGrantList) DeepCopy() *CounterMeasureNamespaceGrantList {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectReference)
+	out := new(CounterMeasureNamespaceGrantList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasureNamespaceGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OnEventSpec) DeepCopyInto(out *OnEventSpec) {
+func (in *CounterMeasureNamespaceGrantSpec) DeepCopyInto(out *CounterMeasureNamespaceGrantSpec) {
 	*out = *in
-	if in.SourceSelector != nil {
-		in, out := &in.SourceSelector, &out.SourceSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SuppressionPolicy != nil {
-		in, out := &in.SuppressionPolicy, &out.SuppressionPolicy
-		*out = new(SuppressionPolicySpec)
-		(*in).DeepCopyInto(*out)
+	if in.FromNamespaces != nil {
+		in, out := &in.FromNamespaces, &out.FromNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnEventSpec.
-func (in *OnEventSpec) DeepCopy() *OnEventSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureNamespaceGrantSpec.
+func (in *CounterMeasureNamespaceGrantSpec) DeepCopy() *CounterMeasureNamespaceGrantSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OnEventSpec)
+	out := new(CounterMeasureNamespaceGrantSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PatchSpec) DeepCopyInto(out *PatchSpec) {
+func (in *CounterMeasurePolicy) DeepCopyInto(out *CounterMeasurePolicy) {
 	*out = *in
-	out.TargetObjectRef = in.TargetObjectRef
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchSpec.
-func (in *PatchSpec) DeepCopy() *PatchSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasurePolicy.
+func (in *CounterMeasurePolicy) DeepCopy() *CounterMeasurePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(PatchSpec)
+	out := new(CounterMeasurePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasurePolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodReference) DeepCopyInto(out *PodReference) {
+func (in *CounterMeasurePolicyList) DeepCopyInto(out *CounterMeasurePolicyList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CounterMeasurePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodReference.
-func (in *PodReference) DeepCopy() *PodReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasurePolicyList.
+func (in *CounterMeasurePolicyList) DeepCopy() *CounterMeasurePolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(PodReference)
+	out := new(CounterMeasurePolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasurePolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RestartSpec) DeepCopyInto(out *RestartSpec) {
+func (in *CounterMeasurePolicySpec) DeepCopyInto(out *CounterMeasurePolicySpec) {
 	*out = *in
-	out.DeploymentRef = in.DeploymentRef
+	if in.RateLimits != nil {
+		in, out := &in.RateLimits, &out.RateLimits
+		*out = make([]ActionRateLimit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = new(TargetPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartSpec.
-func (in *RestartSpec) DeepCopy() *RestartSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasurePolicySpec.
+func (in *CounterMeasurePolicySpec) DeepCopy() *CounterMeasurePolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RestartSpec)
+	out := new(CounterMeasurePolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SuppressionPolicySpec) DeepCopyInto(out *SuppressionPolicySpec) {
+func (in *CounterMeasureRun) DeepCopyInto(out *CounterMeasureRun) {
 	*out = *in
-	if in.Duration != nil {
-		in, out := &in.Duration, &out.Duration
-		*out = new(v1.Duration)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureRun.
+func (in *CounterMeasureRun) DeepCopy() *CounterMeasureRun {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasureRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureRunList) DeepCopyInto(out *CounterMeasureRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CounterMeasureRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureRunList.
+func (in *CounterMeasureRunList) DeepCopy() *CounterMeasureRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasureRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureRunSpec) DeepCopyInto(out *CounterMeasureRunSpec) {
+	*out = *in
+	in.Event.DeepCopyInto(&out.Event)
+	if in.PlannedActions != nil {
+		in, out := &in.PlannedActions, &out.PlannedActions
+		*out = make([]PlannedAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]Action, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureRunSpec.
+func (in *CounterMeasureRunSpec) DeepCopy() *CounterMeasureRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureRunStatus) DeepCopyInto(out *CounterMeasureRunStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]ActionResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureRunStatus.
+func (in *CounterMeasureRunStatus) DeepCopy() *CounterMeasureRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureSpec) DeepCopyInto(out *CounterMeasureSpec) {
+	*out = *in
+	in.OnEvent.DeepCopyInto(&out.OnEvent)
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]Action, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(TemplateReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(VerifySpec)
+		**out = **in
+	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(ApprovalSpec)
+		**out = **in
+	}
+	if in.RunHistoryLimit != nil {
+		in, out := &in.RunHistoryLimit, &out.RunHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CircuitBreaker != nil {
+		in, out := &in.CircuitBreaker, &out.CircuitBreaker
+		*out = new(CircuitBreakerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(ScheduleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureSpec.
+func (in *CounterMeasureSpec) DeepCopy() *CounterMeasureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureStatus) DeepCopyInto(out *CounterMeasureStatus) {
+	*out = *in
+	if in.LastStatusChangeTime != nil {
+		in, out := &in.LastStatusChangeTime, &out.LastStatusChangeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastVerificationTime != nil {
+		in, out := &in.LastVerificationTime, &out.LastVerificationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PendingReverts != nil {
+		in, out := &in.PendingReverts, &out.PendingReverts
+		*out = make([]PendingRevert, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Suppressions != nil {
+		in, out := &in.Suppressions, &out.Suppressions
+		*out = make([]Suppression, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RecentFailures != nil {
+		in, out := &in.RecentFailures, &out.RecentFailures
+		*out = make([]v1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureStatus.
+func (in *CounterMeasureStatus) DeepCopy() *CounterMeasureStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureTemplate) DeepCopyInto(out *CounterMeasureTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureTemplate.
+func (in *CounterMeasureTemplate) DeepCopy() *CounterMeasureTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasureTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureTemplateList) DeepCopyInto(out *CounterMeasureTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CounterMeasureTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureTemplateList.
+func (in *CounterMeasureTemplateList) DeepCopy() *CounterMeasureTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasureTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureTemplateSpec) DeepCopyInto(out *CounterMeasureTemplateSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]TemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]Action, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureTemplateSpec.
+func (in *CounterMeasureTemplateSpec) DeepCopy() *CounterMeasureTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureTrigger) DeepCopyInto(out *CounterMeasureTrigger) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureTrigger.
+func (in *CounterMeasureTrigger) DeepCopy() *CounterMeasureTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasureTrigger) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureTriggerList) DeepCopyInto(out *CounterMeasureTriggerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CounterMeasureTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureTriggerList.
+func (in *CounterMeasureTriggerList) DeepCopy() *CounterMeasureTriggerList {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureTriggerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CounterMeasureTriggerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureTriggerSpec) DeepCopyInto(out *CounterMeasureTriggerSpec) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureTriggerSpec.
+func (in *CounterMeasureTriggerSpec) DeepCopy() *CounterMeasureTriggerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureTriggerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CounterMeasureTriggerStatus) DeepCopyInto(out *CounterMeasureTriggerStatus) {
+	*out = *in
+	if in.InjectionTime != nil {
+		in, out := &in.InjectionTime, &out.InjectionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Runs != nil {
+		in, out := &in.Runs, &out.Runs
+		*out = make([]TriggeredRun, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CounterMeasureTriggerStatus.
+func (in *CounterMeasureTriggerStatus) DeepCopy() *CounterMeasureTriggerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CounterMeasureTriggerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DebugSpec) DeepCopyInto(out *DebugSpec) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.PodRef = in.PodRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DebugSpec.
+func (in *DebugSpec) DeepCopy() *DebugSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DebugSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeleteSpec) DeepCopyInto(out *DeleteSpec) {
+	*out = *in
+	out.TargetObjectRef = in.TargetObjectRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeleteSpec.
+func (in *DeleteSpec) DeepCopy() *DeleteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeleteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentReference) DeepCopyInto(out *DeploymentReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentReference.
+func (in *DeploymentReference) DeepCopy() *DeploymentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventRecord) DeepCopyInto(out *EventRecord) {
+	*out = *in
+	if in.ActiveTime != nil {
+		in, out := &in.ActiveTime, &out.ActiveTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventRecord.
+func (in *EventRecord) DeepCopy() *EventRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(EventRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardSpec) DeepCopyInto(out *GuardSpec) {
+	*out = *in
+	if in.MinReadyPercent != nil {
+		in, out := &in.MinReadyPercent, &out.MinReadyPercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardSpec.
+func (in *GuardSpec) DeepCopy() *GuardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InferredPatch) DeepCopyInto(out *InferredPatch) {
+	*out = *in
+	out.TargetObjectRef = in.TargetObjectRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferredPatch.
+func (in *InferredPatch) DeepCopy() *InferredPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(InferredPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONPathMatch) DeepCopyInto(out *JSONPathMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPathMatch.
+func (in *JSONPathMatch) DeepCopy() *JSONPathMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPathMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindReference) DeepCopyInto(out *KindReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindReference.
+func (in *KindReference) DeepCopy() *KindReference {
+	if in == nil {
+		return nil
+	}
+	out := new(KindReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
+func (in *ObjectReference) DeepCopy() *ObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSelector) DeepCopyInto(out *ObjectSelector) {
+	*out = *in
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSelector.
+func (in *ObjectSelector) DeepCopy() *ObjectSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnEventSpec) DeepCopyInto(out *OnEventSpec) {
+	*out = *in
+	if in.SourceSelector != nil {
+		in, out := &in.SourceSelector, &out.SourceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SuppressionPolicy != nil {
+		in, out := &in.SuppressionPolicy, &out.SuppressionPolicy
+		*out = new(SuppressionPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnEventSpec.
+func (in *OnEventSpec) DeepCopy() *OnEventSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OnEventSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchSpec) DeepCopyInto(out *PatchSpec) {
+	*out = *in
+	out.TargetObjectRef = in.TargetObjectRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchSpec.
+func (in *PatchSpec) DeepCopy() *PatchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingRevert) DeepCopyInto(out *PendingRevert) {
+	*out = *in
+	in.Event.DeepCopyInto(&out.Event)
+	if in.RevertAt != nil {
+		in, out := &in.RevertAt, &out.RevertAt
+		*out = (*in).DeepCopy()
+	}
+	if in.InferredPatch != nil {
+		in, out := &in.InferredPatch, &out.InferredPatch
+		*out = new(InferredPatch)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingRevert.
+func (in *PendingRevert) DeepCopy() *PendingRevert {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingRevert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedAction) DeepCopyInto(out *PlannedAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedAction.
+func (in *PlannedAction) DeepCopy() *PlannedAction {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodReference) DeepCopyInto(out *PodReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodReference.
+func (in *PodReference) DeepCopy() *PodReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PodReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartSpec) DeepCopyInto(out *RestartSpec) {
+	*out = *in
+	out.DeploymentRef = in.DeploymentRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartSpec.
+func (in *RestartSpec) DeepCopy() *RestartSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleSpec) DeepCopyInto(out *ScheduleSpec) {
+	*out = *in
+	if in.ActiveWindows != nil {
+		in, out := &in.ActiveWindows, &out.ActiveWindows
+		*out = make([]TimeWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.BlackoutWindows != nil {
+		in, out := &in.BlackoutWindows, &out.BlackoutWindows
+		*out = make([]TimeWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleSpec.
+func (in *ScheduleSpec) DeepCopy() *ScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Suppression) DeepCopyInto(out *Suppression) {
+	*out = *in
+	in.Until.DeepCopyInto(&out.Until)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Suppression.
+func (in *Suppression) DeepCopy() *Suppression {
+	if in == nil {
+		return nil
+	}
+	out := new(Suppression)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SuppressionPolicySpec) DeepCopyInto(out *SuppressionPolicySpec) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.GroupBy != nil {
+		in, out := &in.GroupBy, &out.GroupBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SuppressionPolicySpec.
@@ -331,3 +1252,198 @@ func (in *SuppressionPolicySpec) DeepCopy() *SuppressionPolicySpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetPolicy) DeepCopyInto(out *TargetPolicy) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedNamespaces != nil {
+		in, out := &in.DeniedNamespaces, &out.DeniedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedKinds != nil {
+		in, out := &in.AllowedKinds, &out.AllowedKinds
+		*out = make([]KindReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedKinds != nil {
+		in, out := &in.DeniedKinds, &out.DeniedKinds
+		*out = make([]KindReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetPolicy.
+func (in *TargetPolicy) DeepCopy() *TargetPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateParameter) DeepCopyInto(out *TemplateParameter) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateParameter.
+func (in *TemplateParameter) DeepCopy() *TemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateReference) DeepCopyInto(out *TemplateReference) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateReference.
+func (in *TemplateReference) DeepCopy() *TemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeWindow) DeepCopyInto(out *TimeWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeWindow.
+func (in *TimeWindow) DeepCopy() *TimeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggeredRun) DeepCopyInto(out *TriggeredRun) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggeredRun.
+func (in *TriggeredRun) DeepCopy() *TriggeredRun {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggeredRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UndoSpec) DeepCopyInto(out *UndoSpec) {
+	*out = *in
+	if in.RevertAfter != nil {
+		in, out := &in.RevertAfter, &out.RevertAfter
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Delete != nil {
+		in, out := &in.Delete, &out.Delete
+		*out = new(DeleteSpec)
+		**out = **in
+	}
+	if in.Patch != nil {
+		in, out := &in.Patch, &out.Patch
+		*out = new(PatchSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UndoSpec.
+func (in *UndoSpec) DeepCopy() *UndoSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UndoSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerifySpec) DeepCopyInto(out *VerifySpec) {
+	*out = *in
+	out.GracePeriod = in.GracePeriod
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerifySpec.
+func (in *VerifySpec) DeepCopy() *VerifySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VerifySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WaitForSpec) DeepCopyInto(out *WaitForSpec) {
+	*out = *in
+	if in.TargetObjectRef != nil {
+		in, out := &in.TargetObjectRef, &out.TargetObjectRef
+		*out = new(ObjectReference)
+		**out = **in
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(ObjectSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(ConditionMatch)
+		**out = **in
+	}
+	if in.JSONPath != nil {
+		in, out := &in.JSONPath, &out.JSONPath
+		*out = new(JSONPathMatch)
+		**out = **in
+	}
+	out.Timeout = in.Timeout
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaitForSpec.
+func (in *WaitForSpec) DeepCopy() *WaitForSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WaitForSpec)
+	in.DeepCopyInto(out)
+	return out
+}
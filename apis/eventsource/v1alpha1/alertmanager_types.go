@@ -0,0 +1,88 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AlertmanagerSpec defines the desired state of Alertmanager
+type AlertmanagerSpec struct {
+	// `path` is the URL path of the receiver endpoint the Alertmanager webhook_config posts to,
+	// it defaults to /alertmanager/<namespace>/<name>.
+	// +kubebuilder:validation:Pattern=`^/.*`
+	// +optional
+	Path string `json:"path,omitempty"`
+	// `auth` how the requests of Alertmanager are authenticated, set the matching credentials in the
+	// http_config of the webhook_config. Alertmanager doesn't sign its requests so hmac can't be used.
+	Auth ReceiverAuth `json:"auth"`
+}
+
+// AlertmanagerStatus defines the observed state of Alertmanager
+type AlertmanagerStatus struct {
+	State StateType `json:"state,omitempty"`
+	// `path` is the URL path the receiver endpoint is serving
+	Path       string             `json:"path,omitempty"`
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.path`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.state`
+// +kubebuilder:resource:shortName=aes
+// Alertmanager is the Schema for the alertmanagers API, an event source receiving the
+// alerts posted by an Alertmanager webhook receiver.
+type Alertmanager struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AlertmanagerSpec   `json:"spec,omitempty"`
+	Status AlertmanagerStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AlertmanagerList contains a list of Alertmanager
+type AlertmanagerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Alertmanager `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Alertmanager{}, &AlertmanagerList{})
+}
+
+// ValidateAuth checks exactly one authentication method Alertmanager supports is set
+func (a *Alertmanager) ValidateAuth() error {
+	if a.Spec.Auth.HMAC != nil {
+		return errors.New("alertmanager doesn't sign its requests, use bearerToken or basicAuth authentication")
+	}
+	return a.Spec.Auth.Validate()
+}
+
+// GetPath the URL path of the receiver endpoint for this event source
+func (a *Alertmanager) GetPath() string {
+	if len(a.Spec.Path) > 0 {
+		return a.Spec.Path
+	}
+	return fmt.Sprintf("/alertmanager/%s/%s", a.Namespace, a.Name)
+}
@@ -34,3 +34,14 @@ var (
 	// AddToScheme adds the types in this group-version to the given scheme.
 	AddToScheme = SchemeBuilder.AddToScheme
 )
+
+// The kinds of the event sources, event sources of different kinds may share a name
+const (
+	AlertmanagerKind     = "Alertmanager"
+	HTTPEventsKind       = "HTTPEvents"
+	KubernetesEventsKind = "KubernetesEvents"
+	NodeConditionKind    = "NodeCondition"
+	PodStatusKind        = "PodStatus"
+	PrometheusKind       = "Prometheus"
+	ScheduleKind         = "Schedule"
+)
@@ -0,0 +1,149 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HMACAuth authenticates the requests by the HMAC signature of their body
+type HMACAuth struct {
+	// `secretKeyRef` the key of a Secret in the namespace of the event source holding the HMAC key.
+	SecretKeyRef corev1.SecretKeySelector `json:"secretKeyRef"`
+	// `header` holding the hex encoded signature.
+	// +kubebuilder:default=X-Signature-256
+	// +optional
+	Header string `json:"header,omitempty"`
+	// `algorithm` of the hash used to sign the body.
+	// +kubebuilder:validation:Enum=sha1;sha256;sha512
+	// +kubebuilder:default=sha256
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+	// `prefix` of the signature in the header, for example sha256=
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// ReceiverAuth how the requests posted to the receiver endpoint of an event source are authenticated,
+// exactly one method has to be set.
+type ReceiverAuth struct {
+	// `bearerToken` the key of a Secret in the namespace of the event source holding the token
+	// sent in the Authorization header.
+	// +optional
+	BearerToken *corev1.SecretKeySelector `json:"bearerToken,omitempty"`
+	// `basicAuth` a Secret of type 'kubernetes.io/basic-auth' in the namespace of the event source
+	// holding the username and password sent in the Authorization header.
+	// +optional
+	BasicAuth *corev1.LocalObjectReference `json:"basicAuth,omitempty"`
+	// `hmac` signature of the body.
+	// +optional
+	HMAC *HMACAuth `json:"hmac,omitempty"`
+}
+
+// EventMapping maps the fields of the payload to the event, the values are JSONPath templates
+// like {.data.pod}, text outside of the braces is kept as is. The payload of a CloudEvent is
+// its structured mode envelope, the attributes with the payload under data, in either mode.
+type EventMapping struct {
+	// `name` of the event, the type of a CloudEvent when not set.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// `data` of the event by key, the top level fields of the payload (or of the data of
+	// a CloudEvent) when not set.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// HTTPEventsSpec defines the desired state of HTTPEvents
+type HTTPEventsSpec struct {
+	// `path` is the URL path of the receiver endpoint the events are posted to,
+	// it defaults to /events/<namespace>/<name>.
+	// +kubebuilder:validation:Pattern=`^/.*`
+	// +optional
+	Path string `json:"path,omitempty"`
+	// `auth` of the requests
+	Auth ReceiverAuth `json:"auth"`
+	// `mapping` of the payload to the event
+	// +optional
+	Mapping EventMapping `json:"mapping,omitempty"`
+}
+
+// HTTPEventsStatus defines the observed state of HTTPEvents
+type HTTPEventsStatus struct {
+	State StateType `json:"state,omitempty"`
+	// `path` is the URL path the receiver endpoint is serving
+	Path       string             `json:"path,omitempty"`
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.path`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.state`
+// +kubebuilder:resource:shortName=hes
+// HTTPEvents is the Schema for the httpevents API, an event source receiving CloudEvents and
+// JSON payloads posted to an authenticated endpoint.
+type HTTPEvents struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HTTPEventsSpec   `json:"spec,omitempty"`
+	Status HTTPEventsStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HTTPEventsList contains a list of HTTPEvents
+type HTTPEventsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HTTPEvents `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HTTPEvents{}, &HTTPEventsList{})
+}
+
+// GetPath the URL path of the receiver endpoint for this event source
+func (h *HTTPEvents) GetPath() string {
+	if len(h.Spec.Path) > 0 {
+		return h.Spec.Path
+	}
+	return fmt.Sprintf("/events/%s/%s", h.Namespace, h.Name)
+}
+
+// Validate checks exactly one authentication method is set
+func (a *ReceiverAuth) Validate() error {
+	methods := 0
+	if a.BearerToken != nil {
+		methods++
+	}
+	if a.BasicAuth != nil {
+		methods++
+	}
+	if a.HMAC != nil {
+		methods++
+	}
+
+	if methods != 1 {
+		return errors.New("exactly one of bearerToken, basicAuth or hmac authentication is required")
+	}
+	return nil
+}
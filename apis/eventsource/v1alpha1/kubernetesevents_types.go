@@ -0,0 +1,78 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KubernetesEventsSpec defines the desired state of KubernetesEvents, the filters are all
+// optional and a core/v1 Event has to match every filter that is set to be published.
+type KubernetesEventsSpec struct {
+	// `reasons` the Event has to have one of, for example BackOff or FailedScheduling.
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+	// `involvedKinds` the kinds the involved object of the Event has to be one of, for example Pod.
+	// +optional
+	InvolvedKinds []string `json:"involvedKinds,omitempty"`
+	// `type` of the Event, either Normal or Warning.
+	// +kubebuilder:validation:Enum=Normal;Warning
+	// +optional
+	Type string `json:"type,omitempty"`
+	// `namespaceSelector` selects the namespaces the Events are watched in, only the
+	// Events in the namespace of this event source are watched when not set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// `messageRegex` a regular expression the message of the Event has to match.
+	// +optional
+	MessageRegex string `json:"messageRegex,omitempty"`
+}
+
+// KubernetesEventsStatus defines the observed state of KubernetesEvents
+type KubernetesEventsStatus struct {
+	State      StateType          `json:"state,omitempty"`
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Reasons",type=string,JSONPath=`.spec.reasons`
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.state`
+// +kubebuilder:resource:shortName=kes
+// KubernetesEvents is the Schema for the kubernetesevents API, an event source publishing
+// the core/v1 Events matching its filters.
+type KubernetesEvents struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KubernetesEventsSpec   `json:"spec,omitempty"`
+	Status KubernetesEventsStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KubernetesEventsList contains a list of KubernetesEvents
+type KubernetesEventsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KubernetesEvents `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KubernetesEvents{}, &KubernetesEventsList{})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeConditionTrigger triggers when a condition of a Node has held the status longer than the duration
+type NodeConditionTrigger struct {
+	// `type` of the condition, for example Ready, DiskPressure, MemoryPressure, PIDPressure or
+	// a condition set by the node-problem-detector.
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+	// `status` the condition has to hold.
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status corev1.ConditionStatus `json:"status"`
+	// `for` how long the condition has to hold the status.
+	For metav1.Duration `json:"for"`
+	// `eventName` the name of the published event, Node<type><status> when not set,
+	// for example NodeReadyFalse.
+	// +optional
+	EventName string `json:"eventName,omitempty"`
+}
+
+// NodeConditionSpec defines the desired state of NodeCondition
+type NodeConditionSpec struct {
+	// `selector` of the Nodes that are watched, all the Nodes when not set.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// `conditions` that trigger an event
+	// +kubebuilder:validation:MinItems=1
+	Conditions []NodeConditionTrigger `json:"conditions"`
+}
+
+// NodeConditionStatus defines the observed state of NodeCondition
+type NodeConditionStatus struct {
+	State      StateType          `json:"state,omitempty"`
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.state`
+// +kubebuilder:resource:shortName=ncs
+// NodeCondition is the Schema for the nodeconditions API, an event source publishing the
+// conditions of the Nodes it watches.
+type NodeCondition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeConditionSpec   `json:"spec,omitempty"`
+	Status NodeConditionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NodeConditionList contains a list of NodeCondition
+type NodeConditionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeCondition `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeCondition{}, &NodeConditionList{})
+}
+
+// GetEventName the name of the event published by the trigger
+func (t *NodeConditionTrigger) GetEventName() string {
+	if len(t.EventName) > 0 {
+		return t.EventName
+	}
+	return fmt.Sprintf("Node%s%s", t.Type, t.Status)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RestartThreshold triggers when a container restarted more than the count within the window
+type RestartThreshold struct {
+	// `count` of restarts within the window that has to be exceeded.
+	// +kubebuilder:validation:Minimum=1
+	Count int32 `json:"count"`
+	// `window` the restarts are counted in.
+	Window metav1.Duration `json:"window"`
+}
+
+// PodStatusSpec defines the desired state of PodStatus, an event is published for each of the
+// triggers that are set when a container of a selected Pod meets it.
+type PodStatusSpec struct {
+	// `selector` of the Pods that are watched, all the Pods when not set.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// `namespaceSelector` selects the namespaces the Pods are watched in, only the
+	// Pods in the namespace of this event source are watched when not set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// `reasons` the waiting or terminated state of a container triggers on, for example
+	// CrashLoopBackOff, ImagePullBackOff or OOMKilled. The event is named after the reason.
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+	// `restarts` triggers when a container restarts too often, the event is named Restarts.
+	// +optional
+	Restarts *RestartThreshold `json:"restarts,omitempty"`
+	// `unreadyFor` triggers when a Pod stays unready for longer than the duration, the event
+	// is named Unready.
+	// +optional
+	UnreadyFor *metav1.Duration `json:"unreadyFor,omitempty"`
+}
+
+// PodStatusStatus defines the observed state of PodStatus
+type PodStatusStatus struct {
+	State      StateType          `json:"state,omitempty"`
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Reasons",type=string,JSONPath=`.spec.reasons`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.state`
+// +kubebuilder:resource:shortName=pss
+// PodStatus is the Schema for the podstatuses API, an event source publishing the
+// status of the containers of the Pods it watches.
+type PodStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PodStatusSpec   `json:"spec,omitempty"`
+	Status PodStatusStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PodStatusList contains a list of PodStatus
+type PodStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PodStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PodStatus{}, &PodStatusList{})
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"net/url"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -50,16 +53,67 @@ type AuthSpec struct {
 	SecretReference corev1.SecretReference `json:"secretRef"`
 }
 
+// TLSSpec Spec for verifying the server and presenting a client certificate
+type TLSSpec struct {
+	// `caSecretRef` is a secret with the PEM encoded CA bundle used to verify the server in
+	// the 'ca.crt' key, the system roots are used when not set.
+	// +optional
+	CASecretRef *corev1.SecretReference `json:"caSecretRef,omitempty"`
+	// `certSecretRef` is a secret of type 'kubernetes.io/tls' with the client certificate
+	// presented to the server.
+	// +optional
+	CertSecretRef *corev1.SecretReference `json:"certSecretRef,omitempty"`
+	// `insecureSkipVerify` true if the certificate of the server should not be verified.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// PrometheusQuery a PromQL expression evaluated on every poll, each series it returns is an event
+type PrometheusQuery struct {
+	// `name` of the events published for the series of the expression.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// `expr` the PromQL expression, an instant vector or scalar.
+	// +kubebuilder:validation:MinLength=1
+	Expr string `json:"expr"`
+	// `for` how long a series has to be returned before its event is published, the event is then
+	// published on every poll while the series is returned. The value of the series is part of the
+	// event, so countermeasures should suppress duplicates by the labels of the series with groupBy.
+	// +optional
+	For metav1.Duration `json:"for,omitempty"`
+}
+
 // PrometheusSpec defines the desired state of Prometheus
 type PrometheusSpec struct {
-	Service ServiceReference `json:"service"`
+	// `service` is the prometheus Service in the cluster, either the service or the url is required.
+	// +optional
+	Service ServiceReference `json:"service,omitempty"`
+	// `url` is the address of the prometheus API outside the cluster, for example a Thanos,
+	// Cortex, Mimir or managed Prometheus endpoint. Either the service or the url is required.
+	// +optional
+	URL string `json:"url,omitempty"`
 	// Defines a Kubernetes secret with a type indicating the authentication scheme
 	// for example the type: 'kubernetes.io/basic-auth' indicates basic auth credentials
-	// to prometheus.
+	// to prometheus, while an 'Opaque' or 'kubernetes.io/service-account-token' secret
+	// with a 'token' key is sent as a bearer token. The secrets of the auth and tls specs
+	// have to be in the namespace of the event source.
 	Auth *AuthSpec `json:"auth,omitempty"`
+	// `tls` defines the CA bundle and client certificate used to connect to prometheus.
+	// +optional
+	TLS *TLSSpec `json:"tls,omitempty"`
+	// `headers` sent with every request to prometheus, for example the X-Scope-OrgID of a
+	// multi-tenant backend.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
 
 	PollingInterval metav1.Duration `json:"pollingInterval"`
 	IncludePending  bool            `json:"includePending"`
+	// `queries` evaluated on every poll in addition to reading the active alerts, the names
+	// of the queries must be unique.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Queries []PrometheusQuery `json:"queries,omitempty"`
 }
 
 // PrometheusStatus defines the observed state of Prometheus
@@ -71,9 +125,12 @@ type PrometheusStatus struct {
 type StateType string
 
 const (
-	Polling StateType = "Polling"
-	Error   StateType = "Error"
-	Unknown StateType = "Unknown"
+	Polling   StateType = "Polling"
+	Receiving StateType = "Receiving"
+	Watching  StateType = "Watching"
+	Scheduled StateType = "Scheduled"
+	Error     StateType = "Error"
+	Unknown   StateType = "Unknown"
 )
 
 const (
@@ -81,7 +138,10 @@ const (
 	ReasonReconciling          = "Reconciling"
 	ReasonResourceNotAvailable = "ResourceNotAvailable"
 
-	TypePolling = "Polling"
+	TypePolling   = "Polling"
+	TypeReceiving = "Receiving"
+	TypeWatching  = "Watching"
+	TypeScheduled = "Scheduled"
 )
 
 // +kubebuilder:object:root=true
@@ -112,6 +172,56 @@ func init() {
 	SchemeBuilder.Register(&Prometheus{}, &PrometheusList{})
 }
 
+// Validate checks exactly one of the service or the url is set, and that the secrets are in the
+// namespace of the event source so it can't be used to send the secrets of other namespaces to
+// a url of its choosing. The names of the queries must be unique as they identify the series.
+func (p *PrometheusSpec) Validate(namespace string) error {
+	hasService := p.Service != ServiceReference{}
+	if hasService == (len(p.URL) > 0) {
+		return fmt.Errorf("either a service reference or a url is required")
+	}
+
+	if !hasService {
+		u, err := url.Parse(p.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			return fmt.Errorf("url '%s' is not an absolute http or https url", p.URL)
+		}
+	}
+
+	for _, ref := range p.secretRefs() {
+		if len(ref.Namespace) > 0 && ref.Namespace != namespace {
+			return fmt.Errorf("secret '%s' must be in the namespace of the event source '%s', not '%s'",
+				ref.Name, namespace, ref.Namespace)
+		}
+	}
+
+	names := make(map[string]struct{}, len(p.Queries))
+	for _, query := range p.Queries {
+		if _, ok := names[query.Name]; ok {
+			return fmt.Errorf("query name '%s' is not unique", query.Name)
+		}
+		names[query.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// secretRefs the secrets referenced by the auth and tls specs
+func (p *PrometheusSpec) secretRefs() []*corev1.SecretReference {
+	refs := make([]*corev1.SecretReference, 0, 3)
+	if p.Auth != nil {
+		refs = append(refs, &p.Auth.SecretReference)
+	}
+	if p.TLS != nil {
+		for _, ref := range []*corev1.SecretReference{p.TLS.CASecretRef, p.TLS.CertSecretRef} {
+			if ref != nil {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
 // GetNamespacedName get the NamespacedName of the Service
 func (s *ServiceReference) GetNamespacedName() types.NamespacedName {
 	return types.NamespacedName{
@@ -87,35 +87,45 @@ func (r *Prometheus) ValidateDelete() error {
 
 func ValidatePrometheus(r *Prometheus) error {
 	p := r.Spec
-	if (p.Service == ServiceReference{}) {
-		return fmt.Errorf("service reference is required")
+	if err := p.Validate(r.Namespace); err != nil {
+		return err
 	}
 
-	svc := &corev1.Service{}
-	if err := webhookClient.Get(context.Background(), p.Service.GetNamespacedName(), svc); err != nil {
-		if errors.IsNotFound(err) {
-			return fmt.Errorf("prometheus service '%s' is not found in namespace '%s'", p.Service.Name, p.Service.Namespace)
+	if p.Service != (ServiceReference{}) {
+		svc := &corev1.Service{}
+		if err := webhookClient.Get(context.Background(), p.Service.GetNamespacedName(), svc); err != nil {
+			if errors.IsNotFound(err) {
+				return fmt.Errorf("prometheus service '%s' is not found in namespace '%s'", p.Service.Name, p.Service.Namespace)
+			}
+			return err
 		}
-		return err
 	}
 
-	if p.Auth != nil {
-		secretRef := p.Auth.SecretReference
-		secret := &corev1.Secret{}
-
-		// use the namespace of the event p8s source when none provided for the secret.
-		namespace := secretRef.Namespace
-		if len(namespace) == 0 {
-			namespace = r.Namespace
+	for _, ref := range p.secretRefs() {
+		if err := validateSecret(r, *ref); err != nil {
+			return err
 		}
+	}
 
-		secretName := types.NamespacedName{Namespace: namespace, Name: secretRef.Name}
-		if err := webhookClient.Get(context.Background(), secretName, secret); err != nil {
-			if errors.IsNotFound(err) {
-				return fmt.Errorf("secret '%s' is not found in namespace '%s'", secretRef.Name, secretRef.Namespace)
-			}
-			return err
+	return nil
+}
+
+// validateSecret checks the secret referenced by the event source exists
+func validateSecret(r *Prometheus, secretRef corev1.SecretReference) error {
+	secret := &corev1.Secret{}
+
+	// use the namespace of the event p8s source when none provided for the secret.
+	namespace := secretRef.Namespace
+	if len(namespace) == 0 {
+		namespace = r.Namespace
+	}
+
+	secretName := types.NamespacedName{Namespace: namespace, Name: secretRef.Name}
+	if err := webhookClient.Get(context.Background(), secretName, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("secret '%s' is not found in namespace '%s'", secretRef.Name, secretRef.Namespace)
 		}
+		return err
 	}
 
 	return nil
@@ -138,5 +138,98 @@ var _ = Describe("Prometheus EventSource webhook", func() {
 			Expect(err).Should(HaveOccurred())
 			Ω(err.Error()).Should(Equal("admission webhook \"vprometheus.kb.io\" denied the request: secret 'secret-missing' is not found in namespace 'default'"))
 		})
+
+		It("should fail if the url is not an absolute http url", func() {
+			p8s := &Prometheus{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "eventsource.vilaverde.rocks/v1alpha1",
+					Kind:       "Prometheus",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      EventSourceName,
+					Namespace: EventSourceNamespace,
+				},
+				Spec: PrometheusSpec{
+					URL: "mimir:8080/prometheus",
+				},
+			}
+
+			err := k8sClient.Create(ctx, p8s)
+			Expect(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(Equal("admission webhook \"vprometheus.kb.io\" denied the request: url 'mimir:8080/prometheus' is not an absolute http or https url"))
+		})
+
+		It("should fail if both a service and a url are set", func() {
+			p8s := &Prometheus{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "eventsource.vilaverde.rocks/v1alpha1",
+					Kind:       "Prometheus",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      EventSourceName,
+					Namespace: EventSourceNamespace,
+				},
+				Spec: PrometheusSpec{
+					Service: ServiceReference{
+						Name:      "prom-operated",
+						Namespace: "default",
+					},
+					URL: "https://mimir.example.com/prometheus",
+				},
+			}
+
+			err := k8sClient.Create(ctx, p8s)
+			Expect(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(Equal("admission webhook \"vprometheus.kb.io\" denied the request: either a service reference or a url is required"))
+		})
+
+		It("should fail if a secret is in another namespace", func() {
+			p8s := &Prometheus{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "eventsource.vilaverde.rocks/v1alpha1",
+					Kind:       "Prometheus",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      EventSourceName,
+					Namespace: EventSourceNamespace,
+				},
+				Spec: PrometheusSpec{
+					URL: "https://mimir.example.com/prometheus",
+					Auth: &AuthSpec{
+						SecretReference: corev1.SecretReference{
+							Name:      "operator-token",
+							Namespace: "kube-system",
+						},
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, p8s)
+			Expect(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(Equal("admission webhook \"vprometheus.kb.io\" denied the request: secret 'operator-token' must be in the namespace of the event source 'default', not 'kube-system'"))
+		})
+
+		It("should fail if the query names are not unique", func() {
+			p8s := &Prometheus{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "eventsource.vilaverde.rocks/v1alpha1",
+					Kind:       "Prometheus",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      EventSourceName,
+					Namespace: EventSourceNamespace,
+				},
+				Spec: PrometheusSpec{
+					URL: "https://mimir.example.com/prometheus",
+					Queries: []PrometheusQuery{
+						{Name: "InstanceDown", Expr: "up == 0"},
+						{Name: "InstanceDown", Expr: "probe_success == 0"},
+					},
+				},
+			}
+
+			Expect(p8s.Spec.Validate(EventSourceNamespace)).Should(MatchError("query name 'InstanceDown' is not unique"))
+			Expect(k8sClient.Create(ctx, p8s)).ShouldNot(Succeed())
+		})
 	})
 })
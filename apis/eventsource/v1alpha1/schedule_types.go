@@ -0,0 +1,77 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScheduleSpec defines the desired state of Schedule
+type ScheduleSpec struct {
+	// `schedule` is a cron expression for when the event is published, for example '0 3 * * *'.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// `timezone` is the IANA name of the timezone of the schedule, for example 'America/New_York',
+	// UTC when not set.
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+	// `eventName` the name of the published event.
+	// +kubebuilder:validation:MinLength=1
+	EventName string `json:"eventName"`
+	// `data` of the published event, the scheduledTime key is always set to the time the
+	// event was scheduled for.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// ScheduleStatus defines the observed state of Schedule
+type ScheduleStatus struct {
+	State StateType `json:"state,omitempty"`
+	// `lastScheduleTime` the last time the event was published.
+	// +optional
+	LastScheduleTime *metav1.Time       `json:"lastScheduleTime,omitempty"`
+	Conditions       []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Event",type=string,JSONPath=`.spec.eventName`
+// +kubebuilder:printcolumn:name="Last Schedule",type=date,JSONPath=`.status.lastScheduleTime`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.state`
+// +kubebuilder:resource:shortName=ses
+// Schedule is the Schema for the schedules API, an event source publishing an event on a cron schedule.
+type Schedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ScheduleSpec   `json:"spec,omitempty"`
+	Status ScheduleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ScheduleList contains a list of Schedule
+type ScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Schedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Schedule{}, &ScheduleList{})
+}
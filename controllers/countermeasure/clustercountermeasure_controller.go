@@ -0,0 +1,220 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package countermeasure
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/actions"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/reconciler"
+)
+
+// ClusterCounterMeasureReconciler reconciles a ClusterCounterMeasure object, which is run by the
+// same consumer manager as the CounterMeasures, converted to a CounterMeasure with no namespace.
+type ClusterCounterMeasureReconciler struct {
+	reconciler.ReconcilerBase
+	ConsumerManager manager.Manager[*v1alpha1.CounterMeasure]
+	Reverter        actions.Reverter
+	Log             logr.Logger
+
+	templates templateGenerations
+}
+
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=clustercountermeasures,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=clustercountermeasures/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=clustercountermeasures/finalizers,verbs=update
+
+// Reconcile validates the ClusterCounterMeasure and adds it to the consumer manager
+func (r *ClusterCounterMeasureReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	ccm := &v1alpha1.ClusterCounterMeasure{}
+	err := r.GetClient().Get(ctx, req.NamespacedName, ccm)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("ClusterCounterMeasure resource not found", "name", req.Name)
+			r.ConsumerManager.Remove(req.NamespacedName)
+			r.templates.forget(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		logger.Error(err, "Error getting ClusterCounterMeasure resource object")
+		return ctrl.Result{}, err
+	}
+
+	if _, ok := ccm.Annotations[v1alpha1.ResetCircuitBreakerAnnotation]; ok {
+		if err := r.resetCircuitBreaker(ctx, ccm); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	cm := ccm.AsCounterMeasure()
+
+	// expand the actions of the referenced template before they're validated and run
+	template, err := actions.ResolveTemplate(ctx, r.GetClient(), cm)
+	if err != nil {
+		return r.HandleError(ctx, ccm.ObjectMeta, err)
+	}
+
+	var requeueAfter time.Duration
+	if r.Reverter != nil && len(cm.Status.PendingReverts) > 0 {
+		requeueAfter, err = r.Reverter.Revert(ctx, cm)
+		if err != nil {
+			logger.Error(err, "failed to revert actions", "name", req.Name)
+		}
+	}
+
+	if r.ConsumerManager.Exists(ccm.ObjectMeta) {
+		if !r.templates.changed(req.NamespacedName, template) {
+			result, err := r.HandleSuccess(ctx, ccm.ObjectMeta)
+			return withRequeue(result, err, requeueAfter)
+		}
+
+		logger.Info("ClusterCounterMeasure template changed", "name", req.Name)
+		r.ConsumerManager.Remove(req.NamespacedName)
+	}
+
+	logger.Info("Reconciling ClusterCounterMeasure", "name", req.Name)
+
+	if len(ccm.Status.Conditions) == 0 {
+		err = r.updateStatus(ctx, ccm.ObjectMeta, func(status *v1alpha1.CounterMeasureStatus) {
+			meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+				Type:               v1alpha1.TypeMonitoring,
+				Status:             metav1.ConditionUnknown,
+				ObservedGeneration: ccm.Generation,
+				Reason:             v1alpha1.ReasonReconciling,
+				Message:            "Initializing",
+			})
+			status.LastStatus = v1alpha1.Unknown
+		})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	spec := ccm.Spec.DeepCopy()
+	spec.CounterMeasureSpec = cm.Spec
+	if err := v1alpha1.ValidateClusterSpec(spec); err != nil {
+		return r.HandleError(ctx, ccm.ObjectMeta, err)
+	}
+
+	if err := r.ConsumerManager.Add(cm); err != nil {
+		return r.HandleError(ctx, ccm.ObjectMeta, err)
+	}
+	r.templates.observe(req.NamespacedName, template)
+
+	result, err := r.HandleSuccess(ctx, ccm.ObjectMeta)
+	return withRequeue(result, err, requeueAfter)
+}
+
+// resetCircuitBreaker reset the circuit breaker and remove the annotation that requested it
+func (r *ClusterCounterMeasureReconciler) resetCircuitBreaker(ctx context.Context, ccm *v1alpha1.ClusterCounterMeasure) error {
+	err := actions.ResetCircuitBreaker(ctx, r.GetClient(), client.ObjectKeyFromObject(ccm),
+		v1alpha1.ReasonReset, "Reset with the annotation "+v1alpha1.ResetCircuitBreakerAnnotation)
+	if err != nil {
+		return err
+	}
+	r.GetRecorder().Event(ccm, "Normal", v1alpha1.ReasonReset, "Circuit breaker reset")
+
+	patch := client.MergeFrom(ccm.DeepCopy())
+	delete(ccm.Annotations, v1alpha1.ResetCircuitBreakerAnnotation)
+	return r.GetClient().Patch(ctx, ccm, patch)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterCounterMeasureReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.OnError = r.HandleErrorAndRequeue
+	r.OnSuccess = r.HandleSuccess
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ClusterCounterMeasure{}).
+		Watches(&source.Kind{Type: &v1alpha1.CounterMeasureTemplate{}},
+			handler.EnqueueRequestsFromMapFunc(clusterCounterMeasuresForTemplate(mgr.GetClient()))).
+		Complete(r)
+}
+
+func (r *ClusterCounterMeasureReconciler) HandleSuccess(ctx context.Context, objectMeta metav1.ObjectMeta) (ctrl.Result, error) {
+	err := r.updateStatus(ctx, objectMeta, func(status *v1alpha1.CounterMeasureStatus) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               v1alpha1.TypeMonitoring,
+			ObservedGeneration: objectMeta.Generation,
+			Status:             metav1.ConditionTrue,
+			Reason:             v1alpha1.ReasonSucceeded,
+		})
+		status.LastStatus = v1alpha1.Monitoring
+	})
+
+	return ctrl.Result{}, err
+}
+
+func (r *ClusterCounterMeasureReconciler) HandleErrorAndRequeue(ctx context.Context, objectMeta metav1.ObjectMeta, err error, requeueAfter time.Duration) (ctrl.Result, error) {
+	r.GetRecorder().Event(&v1alpha1.ClusterCounterMeasure{ObjectMeta: objectMeta}, "Warning", "ProcessingError", err.Error())
+
+	updateErr := r.updateStatus(ctx, objectMeta, func(status *v1alpha1.CounterMeasureStatus) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               v1alpha1.TypeMonitoring,
+			ObservedGeneration: objectMeta.Generation,
+			Status:             metav1.ConditionTrue,
+			Reason:             v1alpha1.ReasonSucceeded,
+			Message:            err.Error(),
+		})
+		status.LastStatus = v1alpha1.Error
+	})
+
+	if updateErr != nil {
+		return ctrl.Result{}, updateErr
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// updateStatus re-fetches the ClusterCounterMeasure and applies the mutation to its status
+func (r *ClusterCounterMeasureReconciler) updateStatus(ctx context.Context, objectMeta metav1.ObjectMeta, mutate func(*v1alpha1.CounterMeasureStatus)) error {
+	logger := log.FromContext(ctx)
+
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		ccm := &v1alpha1.ClusterCounterMeasure{}
+		if err := r.GetClient().Get(ctx, types.NamespacedName{Name: objectMeta.Name}, ccm); err != nil {
+			return err
+		}
+
+		mutate(&ccm.Status)
+		ccm.Status.LastStatusChangeTime = &metav1.Time{Time: time.Now()}
+		return r.GetClient().Status().Update(ctx, ccm)
+	})
+
+	if err != nil {
+		logger.Error(err, "failed to update cluster countermeasure status")
+	}
+
+	return err
+}
@@ -26,9 +26,13 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/actions"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/reconciler"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -38,7 +42,10 @@ import (
 type CounterMeasureReconciler struct {
 	reconciler.ReconcilerBase
 	ConsumerManager manager.Manager[*v1alpha1.CounterMeasure]
+	Reverter        actions.Reverter
 	Log             logr.Logger
+
+	templates templateGenerations
 }
 
 // Refer to the following URL for the K8s API groups:
@@ -47,6 +54,10 @@ type CounterMeasureReconciler struct {
 //+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasures,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasures/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasures/finalizers,verbs=update
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasurepolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasurenamespacegrants,verbs=get;list;watch
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasuretemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=impersonate
 //+kubebuilder:rbac:groups=apps,resources=*,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=autoscaling,resources=*,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=batch,resources=*,verbs=get;list;watch;create;update;patch;delete
@@ -74,6 +85,7 @@ func (r *CounterMeasureReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			logger.Info("CounterMeasure resource not found", "name", req.Name, "namespace", req.Namespace)
 			// Notify the monitoring service to stop monitoring the NamespaceName
 			r.ConsumerManager.Remove(req.NamespacedName)
+			r.templates.forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 
@@ -82,8 +94,33 @@ func (r *CounterMeasureReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
+	if _, ok := counterMeasureCR.Annotations[v1alpha1.ResetCircuitBreakerAnnotation]; ok {
+		if err := r.resetCircuitBreaker(ctx, counterMeasureCR); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// expand the actions of the referenced template before they're validated and run
+	template, err := actions.ResolveTemplate(ctx, r.GetClient(), counterMeasureCR)
+	if err != nil {
+		r.GetRecorder().Event(counterMeasureCR, "Warning", "TemplateError", err.Error())
+		return r.HandleError(ctx, counterMeasureCR.ObjectMeta, err)
+	}
+
+	// run any reverts that are due and requeue to check on the ones that remain
+	requeueAfter, err := r.revertDue(ctx, counterMeasureCR)
+	if err != nil {
+		logger.Error(err, "failed to revert actions", "name", req.Name, "namespace", req.Namespace)
+	}
+
 	if r.ConsumerManager.Exists(counterMeasureCR.ObjectMeta) {
-		return r.HandleSuccess(ctx, counterMeasureCR.ObjectMeta)
+		if !r.templates.changed(req.NamespacedName, template) {
+			result, err := r.HandleSuccess(ctx, counterMeasureCR.ObjectMeta)
+			return withRequeue(result, err, requeueAfter)
+		}
+
+		logger.Info("CounterMeasure template changed", "name", req.Name, "namespace", req.Namespace)
+		r.ConsumerManager.Remove(req.NamespacedName)
 	}
 
 	logger.Info("Reconciling CounterMeasure", "name", req.Name, "namespace", req.Namespace)
@@ -104,8 +141,44 @@ func (r *CounterMeasureReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	if err != nil {
 		return r.HandleError(ctx, counterMeasureCR.ObjectMeta, err)
 	}
+	r.templates.observe(req.NamespacedName, template)
+
+	result, err := r.HandleSuccess(ctx, counterMeasureCR.ObjectMeta)
+	return withRequeue(result, err, requeueAfter)
+}
+
+// revertDue run the pending reverts that are due, returning when to check the remaining reverts
+func (r *CounterMeasureReconciler) revertDue(ctx context.Context, cm *v1alpha1.CounterMeasure) (time.Duration, error) {
+	if r.Reverter == nil || len(cm.Status.PendingReverts) == 0 {
+		return 0, nil
+	}
+
+	return r.Reverter.Revert(ctx, cm)
+}
+
+// resetCircuitBreaker reset the circuit breaker and remove the annotation that requested it
+func (r *CounterMeasureReconciler) resetCircuitBreaker(ctx context.Context, cm *v1alpha1.CounterMeasure) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Resetting circuit breaker", "name", cm.Name, "namespace", cm.Namespace)
+
+	err := actions.ResetCircuitBreaker(ctx, r.GetClient(), client.ObjectKeyFromObject(cm),
+		v1alpha1.ReasonReset, "Reset with the annotation "+v1alpha1.ResetCircuitBreakerAnnotation)
+	if err != nil {
+		return err
+	}
+	r.GetRecorder().Event(cm, "Normal", v1alpha1.ReasonReset, "Circuit breaker reset")
 
-	return r.HandleSuccess(ctx, counterMeasureCR.ObjectMeta)
+	patch := client.MergeFrom(cm.DeepCopy())
+	delete(cm.Annotations, v1alpha1.ResetCircuitBreakerAnnotation)
+	return r.GetClient().Patch(ctx, cm, patch)
+}
+
+// withRequeue sets the RequeueAfter on a successful result
+func withRequeue(result ctrl.Result, err error, after time.Duration) (ctrl.Result, error) {
+	if err == nil && after > 0 {
+		result.RequeueAfter = after
+	}
+	return result, err
 }
 
 func (r *CounterMeasureReconciler) isValid(ctx context.Context, cm *v1alpha1.CounterMeasure) (bool, error) {
@@ -120,6 +193,8 @@ func (r *CounterMeasureReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.CounterMeasure{}).
+		Watches(&source.Kind{Type: &v1alpha1.CounterMeasureTemplate{}},
+			handler.EnqueueRequestsFromMapFunc(counterMeasuresForTemplate(mgr.GetClient()))).
 		Complete(r)
 }
 
@@ -0,0 +1,106 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package countermeasure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/actions"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/reconciler"
+)
+
+// CounterMeasureRunReconciler executes the CounterMeasureRuns once they are approved
+type CounterMeasureRunReconciler struct {
+	reconciler.ReconcilerBase
+	Executor actions.RunExecutor
+	Log      logr.Logger
+}
+
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasureruns,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasureruns/status,verbs=get;update;patch
+
+// Reconcile executes a CounterMeasureRun pending approval once it's approved, or marks it
+// expired when it's not approved in time.
+func (r *CounterMeasureRunReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	run := &v1alpha1.CounterMeasureRun{}
+	err := r.GetClient().Get(ctx, req.NamespacedName, run)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		logger.Error(err, "Error getting CounterMeasureRun resource object")
+		return ctrl.Result{}, err
+	}
+
+	// only runs waiting for approval need any attention
+	if run.Status.Phase != v1alpha1.PendingApproval {
+		return ctrl.Result{}, nil
+	}
+
+	if run.Spec.ExpiresAt != nil && !run.Spec.ExpiresAt.After(time.Now()) {
+		logger.Info("CounterMeasureRun approval expired", "name", req.Name, "namespace", req.Namespace)
+		r.GetRecorder().Event(run, "Warning", "Expired", "Run was not approved before it expired")
+		return ctrl.Result{}, r.setPhase(ctx, run, v1alpha1.Expired, "Run was not approved before it expired")
+	}
+
+	if !run.IsApproved() {
+		if run.Spec.ExpiresAt == nil {
+			return ctrl.Result{}, nil
+		}
+		// check back when the run is due to expire
+		return ctrl.Result{RequeueAfter: time.Until(run.Spec.ExpiresAt.Time)}, nil
+	}
+
+	logger.Info("Executing approved CounterMeasureRun", "name", req.Name, "namespace", req.Namespace)
+	r.GetRecorder().Event(run, "Normal", "Approved",
+		fmt.Sprintf("Run approved, executing countermeasure '%s'", run.Spec.CounterMeasure))
+
+	if err := r.Executor.ExecuteRun(ctx, run); err != nil {
+		r.GetRecorder().Event(run, "Warning", "ExecutionError", err.Error())
+		return ctrl.Result{}, r.setPhase(ctx, run, v1alpha1.Failed, err.Error())
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *CounterMeasureRunReconciler) setPhase(ctx context.Context, run *v1alpha1.CounterMeasureRun, phase v1alpha1.RunPhase, message string) error {
+	return actions.UpdateRunStatus(ctx, r.GetClient(), client.ObjectKeyFromObject(run), func(status *v1alpha1.CounterMeasureRunStatus) {
+		status.Phase = phase
+		status.Message = message
+		status.CompletionTime = &metav1.Time{Time: time.Now()}
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CounterMeasureRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.CounterMeasureRun{}).
+		Complete(r)
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package countermeasure
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/actions"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/reconciler"
+)
+
+// notInstalledRequeue how long to wait for the countermeasure named by a trigger to be installed
+const notInstalledRequeue = 10 * time.Second
+
+// CounterMeasureTriggerReconciler injects the event of a CounterMeasureTrigger into the event bus
+// and records the runs created for it.
+type CounterMeasureTriggerReconciler struct {
+	reconciler.ReconcilerBase
+	ConsumerManager manager.Manager[*v1alpha1.CounterMeasure]
+	EventBus        *eventbus.EventBus
+	Log             logr.Logger
+}
+
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasuretriggers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasuretriggers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasuretriggers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=countermeasure.vilaverde.rocks,resources=countermeasureruns,verbs=get;list;watch
+
+// Reconcile injects the event of a new CounterMeasureTrigger, after that it keeps the runs created
+// for the event up to date in the status.
+func (r *CounterMeasureTriggerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	trigger := &v1alpha1.CounterMeasureTrigger{}
+	err := r.GetClient().Get(ctx, req.NamespacedName, trigger)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		logger.Error(err, "Error getting CounterMeasureTrigger resource object")
+		return ctrl.Result{}, err
+	}
+
+	if trigger.Status.Phase == v1alpha1.TriggerFailed {
+		return ctrl.Result{}, nil
+	}
+
+	if !trigger.Status.IsInjected() {
+		return r.inject(ctx, trigger)
+	}
+
+	runs, err := actions.TriggeredRuns(ctx, r.GetClient(), trigger)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if reflect.DeepEqual(runs, trigger.Status.Runs) {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, r.updateStatus(ctx, trigger, func(status *v1alpha1.CounterMeasureTriggerStatus) {
+		status.Runs = runs
+	})
+}
+
+// inject publishes the event of the trigger to the countermeasure it names, or to the countermeasures
+// in the namespace of the trigger listening for the event name. The event is never published to the
+// event name itself, which would deliver it to the countermeasures of every namespace.
+func (r *CounterMeasureTriggerReconciler) inject(ctx context.Context, trigger *v1alpha1.CounterMeasureTrigger) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	eventName := trigger.Spec.EventName
+	var targets []v1alpha1.CounterMeasure
+	if len(trigger.Spec.CounterMeasure) > 0 {
+		key := types.NamespacedName{Namespace: trigger.Namespace, Name: trigger.Spec.CounterMeasure}
+		cm := &v1alpha1.CounterMeasure{}
+		if err := r.GetClient().Get(ctx, key, cm); err != nil {
+			if errors.IsNotFound(err) {
+				return ctrl.Result{}, r.fail(ctx, trigger, fmt.Sprintf("countermeasure '%s' not found", key.Name))
+			}
+			return ctrl.Result{}, err
+		}
+
+		if len(eventName) == 0 {
+			eventName = cm.Spec.OnEvent.EventName
+		}
+		targets = append(targets, *cm)
+	} else {
+		if len(eventName) == 0 {
+			return ctrl.Result{}, r.fail(ctx, trigger, "either counterMeasure or eventName is required")
+		}
+
+		listening, err := actions.ListeningCounterMeasures(ctx, r.GetClient(), trigger.Namespace, eventName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(listening) == 0 {
+			return ctrl.Result{}, r.fail(ctx, trigger,
+				fmt.Sprintf("no countermeasure in namespace '%s' listens for event '%s'", trigger.Namespace, eventName))
+		}
+		targets = listening
+	}
+
+	// without a subscription the event would be dropped by the bus
+	for _, cm := range targets {
+		if !r.ConsumerManager.Exists(cm.ObjectMeta) {
+			logger.Info("Waiting for the countermeasure to be installed", "countermeasure", cm.Name)
+			return ctrl.Result{RequeueAfter: notInstalledRequeue}, nil
+		}
+	}
+
+	// claim the trigger before publishing, the update fails with a conflict when another reconcile
+	// of the same trigger got there first so the event is published only once.
+	event := actions.NewTriggerEvent(trigger, eventName)
+	trigger.Status.Phase = v1alpha1.TriggerInjected
+	trigger.Status.Message = ""
+	trigger.Status.EventName = eventName
+	trigger.Status.EventKey = event.Key()
+	trigger.Status.InjectionTime = &metav1.Time{Time: event.ActiveTime}
+	if err := r.GetClient().Status().Update(ctx, trigger); err != nil {
+		if errors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	for _, cm := range targets {
+		if err := r.EventBus.Publish(actions.TriggerTopic(client.ObjectKeyFromObject(&cm)), event); err != nil {
+			return ctrl.Result{}, r.fail(ctx, trigger, fmt.Sprintf("failed to publish event '%s': %s", eventName, err))
+		}
+	}
+
+	logger.Info("Injected event", "event", eventName, "name", trigger.Name, "namespace", trigger.Namespace)
+	r.GetRecorder().Event(trigger, "Normal", "Injected", fmt.Sprintf("Injected event '%s'", eventName))
+
+	return ctrl.Result{}, nil
+}
+
+// fail records why the event of the trigger can't be injected
+func (r *CounterMeasureTriggerReconciler) fail(ctx context.Context, trigger *v1alpha1.CounterMeasureTrigger, message string) error {
+	r.GetRecorder().Event(trigger, "Warning", "InjectionError", message)
+	return r.updateStatus(ctx, trigger, func(status *v1alpha1.CounterMeasureTriggerStatus) {
+		status.Phase = v1alpha1.TriggerFailed
+		status.Message = message
+	})
+}
+
+// updateStatus re-fetches the CounterMeasureTrigger and applies the mutation to its status
+func (r *CounterMeasureTriggerReconciler) updateStatus(ctx context.Context, trigger *v1alpha1.CounterMeasureTrigger, mutate func(*v1alpha1.CounterMeasureTriggerStatus)) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest := &v1alpha1.CounterMeasureTrigger{}
+		if err := r.GetClient().Get(ctx, client.ObjectKeyFromObject(trigger), latest); err != nil {
+			return err
+		}
+
+		mutate(&latest.Status)
+		return r.GetClient().Status().Update(ctx, latest)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CounterMeasureTriggerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.CounterMeasureTrigger{}).
+		Watches(&source.Kind{Type: &v1alpha1.CounterMeasureRun{}},
+			handler.EnqueueRequestsFromMapFunc(triggersForRun(mgr.GetClient()))).
+		Complete(r)
+}
+
+// triggersForRun maps a CounterMeasureRun to the CounterMeasureTriggers in the same namespace that
+// injected the event of the run.
+func triggersForRun(c client.Client) func(client.Object) []reconcile.Request {
+	return func(run client.Object) []reconcile.Request {
+		eventKey, ok := run.GetLabels()[v1alpha1.EventKeyLabel]
+		if !ok {
+			return nil
+		}
+
+		list := &v1alpha1.CounterMeasureTriggerList{}
+		if err := c.List(context.Background(), list, client.InNamespace(run.GetNamespace())); err != nil {
+			log.Log.Error(err, "unable to list countermeasure triggers for run", "name", run.GetName())
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0)
+		for _, trigger := range list.Items {
+			if trigger.Status.EventKey == eventKey {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&trigger)})
+			}
+		}
+		return requests
+	}
+}
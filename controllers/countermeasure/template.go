@@ -0,0 +1,94 @@
+package countermeasure
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+)
+
+// templateGenerations tracks the generation of the template each countermeasure was added with, so
+// the countermeasures are added again when their template changes.
+type templateGenerations struct {
+	mux         sync.Mutex
+	generations map[types.NamespacedName]int64
+}
+
+// changed checks if the template is not the one the countermeasure was added with
+func (t *templateGenerations) changed(key types.NamespacedName, template *v1alpha1.CounterMeasureTemplate) bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	generation, ok := t.generations[key]
+	if template == nil {
+		return ok
+	}
+	return !ok || generation != template.Generation
+}
+
+// observe records the template the countermeasure was added with
+func (t *templateGenerations) observe(key types.NamespacedName, template *v1alpha1.CounterMeasureTemplate) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.generations == nil {
+		t.generations = make(map[types.NamespacedName]int64)
+	}
+
+	if template == nil {
+		delete(t.generations, key)
+		return
+	}
+	t.generations[key] = template.Generation
+}
+
+// forget the template of a countermeasure that was removed
+func (t *templateGenerations) forget(key types.NamespacedName) {
+	t.observe(key, nil)
+}
+
+// counterMeasuresForTemplate maps a CounterMeasureTemplate to the CounterMeasures in the same
+// namespace referencing it.
+func counterMeasuresForTemplate(c client.Client) func(client.Object) []reconcile.Request {
+	return func(template client.Object) []reconcile.Request {
+		list := &v1alpha1.CounterMeasureList{}
+		if err := c.List(context.Background(), list, client.InNamespace(template.GetNamespace())); err != nil {
+			log.Log.Error(err, "unable to list countermeasures for template", "name", template.GetName())
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0)
+		for _, cm := range list.Items {
+			if cm.Spec.Template != nil && cm.Spec.Template.Name == template.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cm)})
+			}
+		}
+		return requests
+	}
+}
+
+// clusterCounterMeasuresForTemplate maps a CounterMeasureTemplate to the ClusterCounterMeasures
+// referencing it.
+func clusterCounterMeasuresForTemplate(c client.Client) func(client.Object) []reconcile.Request {
+	return func(template client.Object) []reconcile.Request {
+		list := &v1alpha1.ClusterCounterMeasureList{}
+		if err := c.List(context.Background(), list); err != nil {
+			log.Log.Error(err, "unable to list cluster countermeasures for template", "name", template.GetName())
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0)
+		for _, ccm := range list.Items {
+			ref := ccm.Spec.Template
+			if ref != nil && ref.Name == template.GetName() && ref.Namespace == template.GetNamespace() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&ccm)})
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsource
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer/alertmanager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer/receiver"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/reconciler"
+)
+
+// AlertmanagerReconciler reconciles an Alertmanager object
+type AlertmanagerReconciler struct {
+	reconciler.ReconcilerBase
+	Producers manager.Manager[producer.KeyedEventProducer]
+	Receiver  *receiver.Receiver
+	eventBus  *eventbus.EventBus
+	Log       logr.Logger
+}
+
+//+kubebuilder:rbac:groups=eventsource.vilaverde.rocks,resources=alertmanagers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=eventsource.vilaverde.rocks,resources=alertmanagers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=eventsource.vilaverde.rocks,resources=alertmanagers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile registers a producer serving the authenticated receiver endpoint of the Alertmanager event source.
+func (r *AlertmanagerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logr := log.FromContext(ctx)
+
+	eventSourceCR := &v1alpha1.Alertmanager{}
+	err := r.GetClient().Get(ctx, req.NamespacedName, eventSourceCR)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logr.Info("Alertmanager event source resource not found", "name", req.Name, "namespace", req.Namespace)
+
+			r.Receiver.Unregister(receiver.Source{Kind: v1alpha1.AlertmanagerKind, NamespacedName: req.NamespacedName})
+			err := r.Producers.Remove(req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+
+		logr.Error(err, "Error getting Alertmanager event source resource object")
+		return ctrl.Result{}, err
+	}
+
+	// check for the existence of the event source, in case it's already added and running
+	// there is no need to re-install. This handles re-queues due to status changes.
+	if !r.Producers.Exists(eventSourceCR.ObjectMeta) {
+		if err := eventSourceCR.ValidateAuth(); err != nil {
+			return r.HandleError(ctx, eventSourceCR.ObjectMeta, err)
+		}
+
+		authenticator, err := receiver.NewAuthenticator(r.GetClient(), eventSourceCR.Namespace, eventSourceCR.Spec.Auth)
+		if err != nil {
+			return r.HandleError(ctx, eventSourceCR.ObjectMeta, err)
+		}
+
+		config := alertmanager.AlertmanagerConfig{
+			Key:           manager.ToKindKey(v1alpha1.AlertmanagerKind, eventSourceCR.ObjectMeta),
+			Path:          eventSourceCR.GetPath(),
+			Receiver:      r.Receiver,
+			Authenticator: authenticator,
+		}
+		eventProducer := alertmanager.NewEventProducer(config, r.eventBus)
+
+		// the endpoint is registered before the event source is reported as receiving, replacing
+		// the endpoint of a previous generation, so a path served for another event source is
+		// surfaced on the status.
+		if err := r.Receiver.Register(receiver.Source{Kind: v1alpha1.AlertmanagerKind, NamespacedName: req.NamespacedName}, config.Path, eventProducer); err != nil {
+			return r.HandleErrorAndRequeue(ctx, eventSourceCR.ObjectMeta, err, time.Duration(30*time.Second))
+		}
+
+		// replace the producer of a previous generation
+		if err := r.Producers.Remove(req.NamespacedName); err != nil {
+			return r.HandleError(ctx, eventSourceCR.ObjectMeta, err)
+		}
+
+		if err := r.Producers.Add(eventProducer); err != nil {
+			r.Receiver.Unregister(receiver.Source{Kind: v1alpha1.AlertmanagerKind, NamespacedName: req.NamespacedName})
+			return r.HandleError(ctx, eventSourceCR.ObjectMeta, err)
+		}
+	}
+
+	return r.HandleSuccess(ctx, eventSourceCR.ObjectMeta)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AlertmanagerReconciler) SetupWithManager(mgr ctrl.Manager, bus *eventbus.EventBus) error {
+	r.OnError = r.HandleErrorAndRequeue
+	r.OnSuccess = r.HandleSuccess
+	r.eventBus = bus
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Alertmanager{}).
+		Complete(r)
+}
+
+func (r *AlertmanagerReconciler) HandleSuccess(ctx context.Context, objectMeta metav1.ObjectMeta) (ctrl.Result, error) {
+	err := r.updateStatus(ctx, objectMeta, func(es *v1alpha1.Alertmanager) {
+		meta.SetStatusCondition(&es.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.TypeReceiving,
+			ObservedGeneration: objectMeta.Generation,
+			Status:             metav1.ConditionTrue,
+			Reason:             v1alpha1.ReasonSucceeded,
+		})
+
+		es.Status.State = v1alpha1.Receiving
+		es.Status.Path = es.GetPath()
+	})
+
+	return ctrl.Result{}, err
+}
+
+func (r *AlertmanagerReconciler) HandleErrorAndRequeue(ctx context.Context, objectMeta metav1.ObjectMeta, err error, requeueAfter time.Duration) (ctrl.Result, error) {
+	r.GetRecorder().Event(&v1alpha1.Alertmanager{ObjectMeta: objectMeta}, "Warning", "ProcessingError", err.Error())
+
+	updateErr := r.updateStatus(ctx, objectMeta, func(es *v1alpha1.Alertmanager) {
+		meta.SetStatusCondition(&es.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.TypeReceiving,
+			ObservedGeneration: objectMeta.Generation,
+			Status:             metav1.ConditionFalse,
+			Reason:             v1alpha1.ReasonResourceNotAvailable,
+			Message:            err.Error(),
+		})
+
+		es.Status.State = v1alpha1.Error
+		es.Status.Path = ""
+	})
+
+	if updateErr != nil {
+		return ctrl.Result{}, updateErr
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// updateStatus re-fetches the Alertmanager event source and applies the mutation to its status
+func (r *AlertmanagerReconciler) updateStatus(ctx context.Context, objectMeta metav1.ObjectMeta, mutate func(*v1alpha1.Alertmanager)) error {
+	logger := log.FromContext(ctx)
+
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		eventSourceCR := &v1alpha1.Alertmanager{}
+		ns := types.NamespacedName{Namespace: objectMeta.Namespace, Name: objectMeta.Name}
+		if err := r.GetClient().Get(ctx, ns, eventSourceCR); err != nil {
+			return err
+		}
+
+		mutate(eventSourceCR)
+		return r.GetClient().Status().Update(ctx, eventSourceCR)
+	})
+
+	if err != nil {
+		if errors.IsConflict(err) {
+			logger.Info("409 conflict - failed to update alertmanager event source status, reconcile re-queued.")
+		} else {
+			logger.Error(err, "failed to update alertmanager event source status")
+		}
+	}
+
+	return err
+}
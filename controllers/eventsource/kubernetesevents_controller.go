@@ -0,0 +1,179 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsource
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer/k8sevents"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/reconciler"
+)
+
+// KubernetesEventsReconciler reconciles a KubernetesEvents object
+type KubernetesEventsReconciler struct {
+	reconciler.ReconcilerBase
+	Producers manager.Manager[producer.KeyedEventProducer]
+	eventBus  *eventbus.EventBus
+	clientset kubernetes.Interface
+	Log       logr.Logger
+}
+
+//+kubebuilder:rbac:groups=eventsource.vilaverde.rocks,resources=kubernetesevents,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=eventsource.vilaverde.rocks,resources=kubernetesevents/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=eventsource.vilaverde.rocks,resources=kubernetesevents/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+// Reconcile registers a producer watching the core/v1 Events for the KubernetesEvents event source.
+func (r *KubernetesEventsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logr := log.FromContext(ctx)
+
+	eventSourceCR := &v1alpha1.KubernetesEvents{}
+	err := r.GetClient().Get(ctx, req.NamespacedName, eventSourceCR)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logr.Info("KubernetesEvents event source resource not found", "name", req.Name, "namespace", req.Namespace)
+
+			err := r.Producers.Remove(req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+
+		logr.Error(err, "Error getting KubernetesEvents event source resource object")
+		return ctrl.Result{}, err
+	}
+
+	// check for the existence of the event source, in case it's already added and running
+	// there is no need to re-install. This handles re-queues due to status changes.
+	if !r.Producers.Exists(eventSourceCR.ObjectMeta) {
+		filter, err := k8sevents.NewFilter(eventSourceCR.Spec)
+		if err != nil {
+			return r.HandleError(ctx, eventSourceCR.ObjectMeta, err)
+		}
+
+		// stop watching with the filters of a previous generation
+		if err := r.Producers.Remove(req.NamespacedName); err != nil {
+			return r.HandleError(ctx, eventSourceCR.ObjectMeta, err)
+		}
+
+		config := k8sevents.KubernetesEventsConfig{
+			Key:        manager.ToKindKey(v1alpha1.KubernetesEventsKind, eventSourceCR.ObjectMeta),
+			Filter:     filter,
+			Clientset:  r.clientset,
+			Namespaces: r.GetClient(),
+		}
+
+		err = r.Producers.Add(k8sevents.NewEventProducer(config, r.eventBus))
+		return r.HandleOutcome(ctx, eventSourceCR.ObjectMeta, err)
+	}
+
+	return r.HandleSuccess(ctx, eventSourceCR.ObjectMeta)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KubernetesEventsReconciler) SetupWithManager(mgr ctrl.Manager, bus *eventbus.EventBus) error {
+	r.OnError = r.HandleErrorAndRequeue
+	r.OnSuccess = r.HandleSuccess
+	r.eventBus = bus
+
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	r.clientset = clientset
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.KubernetesEvents{}).
+		Complete(r)
+}
+
+func (r *KubernetesEventsReconciler) HandleSuccess(ctx context.Context, objectMeta metav1.ObjectMeta) (ctrl.Result, error) {
+	err := r.updateStatus(ctx, objectMeta, func(es *v1alpha1.KubernetesEvents) {
+		meta.SetStatusCondition(&es.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.TypeWatching,
+			ObservedGeneration: objectMeta.Generation,
+			Status:             metav1.ConditionTrue,
+			Reason:             v1alpha1.ReasonSucceeded,
+		})
+
+		es.Status.State = v1alpha1.Watching
+	})
+
+	return ctrl.Result{}, err
+}
+
+func (r *KubernetesEventsReconciler) HandleErrorAndRequeue(ctx context.Context, objectMeta metav1.ObjectMeta, err error, requeueAfter time.Duration) (ctrl.Result, error) {
+	r.GetRecorder().Event(&v1alpha1.KubernetesEvents{ObjectMeta: objectMeta}, "Warning", "ProcessingError", err.Error())
+
+	updateErr := r.updateStatus(ctx, objectMeta, func(es *v1alpha1.KubernetesEvents) {
+		meta.SetStatusCondition(&es.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.TypeWatching,
+			ObservedGeneration: objectMeta.Generation,
+			Status:             metav1.ConditionFalse,
+			Reason:             v1alpha1.ReasonResourceNotAvailable,
+			Message:            err.Error(),
+		})
+
+		es.Status.State = v1alpha1.Error
+	})
+
+	if updateErr != nil {
+		return ctrl.Result{}, updateErr
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// updateStatus re-fetches the KubernetesEvents event source and applies the mutation to its status
+func (r *KubernetesEventsReconciler) updateStatus(ctx context.Context, objectMeta metav1.ObjectMeta, mutate func(*v1alpha1.KubernetesEvents)) error {
+	logger := log.FromContext(ctx)
+
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		eventSourceCR := &v1alpha1.KubernetesEvents{}
+		ns := types.NamespacedName{Namespace: objectMeta.Namespace, Name: objectMeta.Name}
+		if err := r.GetClient().Get(ctx, ns, eventSourceCR); err != nil {
+			return err
+		}
+
+		mutate(eventSourceCR)
+		return r.GetClient().Status().Update(ctx, eventSourceCR)
+	})
+
+	if err != nil {
+		if errors.IsConflict(err) {
+			logger.Info("409 conflict - failed to update kubernetes events event source status, reconcile re-queued.")
+		} else {
+			logger.Error(err, "failed to update kubernetes events event source status")
+		}
+	}
+
+	return err
+}
@@ -39,6 +39,13 @@ import (
 	"github.com/dvilaverde/k8s-countermeasures/pkg/reconciler"
 )
 
+const (
+	// bearerTokenKey the key of the bearer token in the auth secret
+	bearerTokenKey = "token"
+	// caBundleKey the key of the CA bundle in the secret of the CA
+	caBundleKey = "ca.crt"
+)
+
 // PrometheusReconciler reconciles a Prometheus object
 type PrometheusReconciler struct {
 	reconciler.ReconcilerBase
@@ -94,8 +101,14 @@ func (r *PrometheusReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		config := prometheus.PrometheusConfig{
 			PollInterval:   eventSourceCR.Spec.PollingInterval.Duration,
 			IncludePending: eventSourceCR.Spec.IncludePending,
-			Key:            manager.ToKey(eventSourceCR.ObjectMeta),
+			Key:            manager.ToKindKey(v1alpha1.PrometheusKind, eventSourceCR.ObjectMeta),
 			Client:         client,
+			Queries:        toQueries(eventSourceCR.Spec.Queries),
+		}
+
+		// stop polling with the queries of a previous generation
+		if err := r.Producers.Remove(req.NamespacedName); err != nil {
+			return r.HandleError(ctx, eventSourceCR.ObjectMeta, err)
 		}
 
 		err = r.Producers.Add(prometheus.NewEventProducer(config, r.eventBus))
@@ -237,13 +250,92 @@ func (r *PrometheusReconciler) MarkInitializing(ctx context.Context, es *v1alpha
 	return err
 }
 
+// toQueries the queries the producer evaluates on every poll
+func toQueries(specs []v1alpha1.PrometheusQuery) []prometheus.Query {
+	queries := make([]prometheus.Query, len(specs))
+	for idx, spec := range specs {
+		queries[idx] = prometheus.Query{
+			Name: spec.Name,
+			Expr: spec.Expr,
+			For:  spec.For.Duration,
+		}
+	}
+	return queries
+}
+
 func (r *PrometheusReconciler) createP8sClient(prom *v1alpha1.Prometheus) (*prometheus.PrometheusService, error) {
 	promConfig := prom.Spec
-	svc := promConfig.Service
+	if err := promConfig.Validate(prom.Namespace); err != nil {
+		return nil, err
+	}
+
+	address, err := r.address(prom)
+	if err != nil {
+		return nil, err
+	}
+
+	clientConfig := prometheus.ClientConfig{
+		Address: address,
+		Headers: promConfig.Headers,
+	}
+
+	if promConfig.Auth != nil {
+		secret, err := r.getSecret(prom, &promConfig.Auth.SecretReference,
+			corev1.SecretTypeBasicAuth, corev1.SecretTypeOpaque, corev1.SecretTypeServiceAccountToken)
+		if err != nil {
+			return nil, err
+		}
+
+		if secret.Type == corev1.SecretTypeBasicAuth {
+			clientConfig.Username = string(secret.Data[corev1.BasicAuthUsernameKey])
+			clientConfig.Password = string(secret.Data[corev1.BasicAuthPasswordKey])
+		} else {
+			clientConfig.BearerToken = string(secret.Data[bearerTokenKey])
+			if len(clientConfig.BearerToken) == 0 {
+				return nil, fmt.Errorf("secret '%s' has no bearer token in the '%s' key", secret.Name, bearerTokenKey)
+			}
+		}
+	}
+
+	if promConfig.TLS != nil {
+		clientConfig.InsecureSkipVerify = promConfig.TLS.InsecureSkipVerify
+
+		if promConfig.TLS.CASecretRef != nil {
+			secret, err := r.getSecret(prom, promConfig.TLS.CASecretRef)
+			if err != nil {
+				return nil, err
+			}
+
+			clientConfig.CA = secret.Data[caBundleKey]
+			if len(clientConfig.CA) == 0 {
+				return nil, fmt.Errorf("secret '%s' has no CA bundle in the '%s' key", secret.Name, caBundleKey)
+			}
+		}
 
+		if promConfig.TLS.CertSecretRef != nil {
+			secret, err := r.getSecret(prom, promConfig.TLS.CertSecretRef, corev1.SecretTypeTLS)
+			if err != nil {
+				return nil, err
+			}
+
+			clientConfig.Cert = secret.Data[corev1.TLSCertKey]
+			clientConfig.Key = secret.Data[corev1.TLSPrivateKeyKey]
+		}
+	}
+
+	return prometheus.NewPrometheusClient(clientConfig)
+}
+
+// address the url of prometheus, or the address of the prometheus Service in the cluster
+func (r *PrometheusReconciler) address(prom *v1alpha1.Prometheus) (string, error) {
+	if len(prom.Spec.URL) > 0 {
+		return prom.Spec.URL, nil
+	}
+
+	svc := prom.Spec.Service
 	serviceObject := &corev1.Service{}
 	if err := r.GetClient().Get(context.Background(), svc.GetNamespacedName(), serviceObject); err != nil {
-		return nil, err
+		return "", err
 	}
 
 	svcPort, found := reconciler.FindNamedPort(serviceObject, svc.TargetPort)
@@ -259,23 +351,25 @@ func (r *PrometheusReconciler) createP8sClient(prom *v1alpha1.Prometheus) (*prom
 		scheme = "https"
 	}
 
-	address := fmt.Sprintf("%v://%v.%v.svc:%v", scheme, svc.Name, svc.Namespace, port)
+	return fmt.Sprintf("%v://%v.%v.svc:%v", scheme, svc.Name, svc.Namespace, port), nil
+}
 
-	var username, password string
-	if promConfig.Auth != nil {
-		secretRef := promConfig.Auth.SecretReference.DeepCopy()
-		if len(secretRef.Namespace) == 0 {
-			secretRef.Namespace = prom.ObjectMeta.Namespace
-		}
-		secret, err := r.GetSecret(secretRef)
-		if err != nil {
-			r.Log.Error(err, fmt.Sprintf("could not lookup secret %s in namespace %s", secretRef.Name, secretRef.Namespace))
-			return nil, err
-		}
+// getSecret reads a secret referenced by the event source, which has to be in the namespace of the
+// event source.
+func (r *PrometheusReconciler) getSecret(prom *v1alpha1.Prometheus, ref *corev1.SecretReference, secretTypes ...corev1.SecretType) (corev1.Secret, error) {
+	secretRef := ref.DeepCopy()
+	if len(secretRef.Namespace) == 0 {
+		secretRef.Namespace = prom.ObjectMeta.Namespace
+	} else if secretRef.Namespace != prom.ObjectMeta.Namespace {
+		return corev1.Secret{}, fmt.Errorf("secret '%s' must be in the namespace of the event source '%s'",
+			secretRef.Name, prom.ObjectMeta.Namespace)
+	}
 
-		username = string(secret.Data["username"])
-		password = string(secret.Data["password"])
+	secret, err := r.GetSecret(secretRef, secretTypes...)
+	if err != nil {
+		r.Log.Error(err, fmt.Sprintf("could not lookup secret %s in namespace %s", secretRef.Name, secretRef.Namespace))
+		return corev1.Secret{}, err
 	}
 
-	return prometheus.NewPrometheusClient(address, username, password)
+	return secret, nil
 }
@@ -24,6 +24,8 @@ import (
 	"os"
 	rt "runtime"
 	"strings"
+	// embed the timezone database for the countermeasure schedules
+	_ "time/tzdata"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -44,6 +46,7 @@ import (
 	"github.com/dvilaverde/k8s-countermeasures/pkg/actions"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/producer"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer/receiver"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/reconciler"
 	"github.com/operator-framework/operator-lib/leader"
 	//+kubebuilder:scaffold:imports
@@ -69,11 +72,20 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var allowCrossNamespaceTargets bool
+	var clusterRunNamespace string
+	var receiverAddr string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&allowCrossNamespaceTargets, "allow-cross-namespace-targets", false,
+		"Allow countermeasures to target objects outside their namespace without a CounterMeasureNamespaceGrant.")
+	flag.StringVar(&clusterRunNamespace, "cluster-run-namespace", "k8s-countermeasures-system",
+		"The namespace the CounterMeasureRuns of the cluster countermeasures are created in.")
+	flag.StringVar(&receiverAddr, "receiver-bind-address", ":8082",
+		"The address the endpoint receiving the events pushed to the event sources binds to.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -148,10 +160,13 @@ func main() {
 	bus := eventbus.NewEventBus(rt.NumCPU())
 	mgr.Add(bus)
 	consumerMgr := actions.NewFromManager(mgr, bus)
+	consumerMgr.AllowCrossNamespaceTargets = allowCrossNamespaceTargets
+	consumerMgr.ClusterRunNamespace = clusterRunNamespace
 
 	cmr := &countermeasure.CounterMeasureReconciler{
 		ReconcilerBase:  reconciler.NewFromManager(mgr),
 		ConsumerManager: consumerMgr,
+		Reverter:        consumerMgr,
 		Log:             ctrl.Log.WithName("controllers").WithName("countermeasure"),
 	}
 	if err = (cmr).SetupWithManager(mgr); err != nil {
@@ -159,18 +174,101 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&countermeasure.ClusterCounterMeasureReconciler{
+		ReconcilerBase:  reconciler.NewFromManager(mgr),
+		ConsumerManager: consumerMgr,
+		Reverter:        consumerMgr,
+		Log:             ctrl.Log.WithName("controllers").WithName("clustercountermeasure"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterCounterMeasure")
+		os.Exit(1)
+	}
+
+	if err = (&countermeasure.CounterMeasureRunReconciler{
+		ReconcilerBase: reconciler.NewFromManager(mgr),
+		Executor:       consumerMgr,
+		Log:            ctrl.Log.WithName("controllers").WithName("countermeasurerun"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CounterMeasureRun")
+		os.Exit(1)
+	}
+
+	if err = (&countermeasure.CounterMeasureTriggerReconciler{
+		ReconcilerBase:  reconciler.NewFromManager(mgr),
+		ConsumerManager: consumerMgr,
+		EventBus:        bus,
+		Log:             ctrl.Log.WithName("controllers").WithName("countermeasuretrigger"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CounterMeasureTrigger")
+		os.Exit(1)
+	}
+
 	producersManager := producer.NewManager(bus)
 	// the source manager is a operator manager because it will be listening to the
 	// done channel in order to stop any running event sources.
 	mgr.Add(producersManager)
+	// the producers are asked if an event is still active when verifying countermeasures
+	consumerMgr.Verifier = producersManager
 	if err = (&eventsource.PrometheusReconciler{
 		ReconcilerBase: reconciler.NewFromManager(mgr),
-		Producers:      producersManager,
+		Producers:      producersManager.ForKind(eventsourcev1alpha1.PrometheusKind),
 	}).SetupWithManager(mgr, bus); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Prometheus")
 		os.Exit(1)
 	}
 
+	if err = (&eventsource.KubernetesEventsReconciler{
+		ReconcilerBase: reconciler.NewFromManager(mgr),
+		Producers:      producersManager.ForKind(eventsourcev1alpha1.KubernetesEventsKind),
+	}).SetupWithManager(mgr, bus); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KubernetesEvents")
+		os.Exit(1)
+	}
+
+	if err = (&eventsource.PodStatusReconciler{
+		ReconcilerBase: reconciler.NewFromManager(mgr),
+		Producers:      producersManager.ForKind(eventsourcev1alpha1.PodStatusKind),
+	}).SetupWithManager(mgr, bus); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PodStatus")
+		os.Exit(1)
+	}
+
+	if err = (&eventsource.NodeConditionReconciler{
+		ReconcilerBase: reconciler.NewFromManager(mgr),
+		Producers:      producersManager.ForKind(eventsourcev1alpha1.NodeConditionKind),
+	}).SetupWithManager(mgr, bus); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NodeCondition")
+		os.Exit(1)
+	}
+	if err = (&eventsource.ScheduleReconciler{
+		ReconcilerBase: reconciler.NewFromManager(mgr),
+		Producers:      producersManager.ForKind(eventsourcev1alpha1.ScheduleKind),
+		Log:            ctrl.Log.WithName("controllers").WithName("Schedule"),
+	}).SetupWithManager(mgr, bus); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Schedule")
+		os.Exit(1)
+	}
+
+	// the receiver serves the endpoints of the event sources that have events pushed to them
+	eventReceiver := receiver.NewReceiver(receiverAddr)
+	mgr.Add(eventReceiver)
+	if err = (&eventsource.AlertmanagerReconciler{
+		ReconcilerBase: reconciler.NewFromManager(mgr),
+		Producers:      producersManager.ForKind(eventsourcev1alpha1.AlertmanagerKind),
+		Receiver:       eventReceiver,
+	}).SetupWithManager(mgr, bus); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Alertmanager")
+		os.Exit(1)
+	}
+	if err = (&eventsource.HTTPEventsReconciler{
+		ReconcilerBase: reconciler.NewFromManager(mgr),
+		Producers:      producersManager.ForKind(eventsourcev1alpha1.HTTPEventsKind),
+		Receiver:       eventReceiver,
+	}).SetupWithManager(mgr, bus); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HTTPEvents")
+		os.Exit(1)
+	}
+
 	v1alpha1.WebhookClient = mgr.GetClient()
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = (&v1alpha1.CounterMeasure{}).SetupWebhookWithManager(mgr); err != nil {
@@ -3,15 +3,19 @@ package actions
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 	"text/template"
+	"time"
 
 	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -35,8 +39,32 @@ type Action interface {
 	SupportsRetry() bool
 }
 
+// RefusedError is returned when an action is refused, by a CounterMeasurePolicy, its guard, a rate limit
+// or the namespace restriction, rather than failing. Refusals aren't retried nor counted against the
+// circuit breaker.
+type RefusedError struct {
+	// Reason the action was refused, for example PolicyDenied
+	Reason string
+	Err    error
+}
+
+func (e *RefusedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RefusedError) Unwrap() error {
+	return e.Err
+}
+
+// IsRefused checks if the error, or any error it wraps, is a RefusedError.
+func IsRefused(err error) bool {
+	var refused *RefusedError
+	return errors.As(err, &refused)
+}
+
 type ActionRunner interface {
-	Run(ActionContext, events.Event)
+	Run(ActionContext, events.Event) error
+	Plan(events.Event) []v1alpha1.PlannedAction
 }
 
 type InMemoryRunner []Action
@@ -45,23 +73,53 @@ type BaseAction struct {
 	DryRun       bool
 	RetryEnabled bool
 	Name         string
-	client       client.Client
+	Guard        *v1alpha1.GuardSpec
+	// Restriction limits the namespaces of the targets, if any.
+	Restriction *NamespaceRestriction
+	client      client.Client
 }
 
 type ActionContext struct {
-	Client         client.Client
+	Client client.Client
+	// ActionClient takes the actions, impersonating the ServiceAccount of the countermeasure
+	// when it has one. Client is used when it's nil.
+	ActionClient   client.Client
 	RestConfig     *rest.Config
 	Recorder       record.EventRecorder
 	CounterMeasure v1alpha1.CounterMeasure
+	// Run is the CounterMeasureRun the action results are recorded on, if any.
+	Run *types.NamespacedName
+	// RateLimiter enforces the rate limits of the CounterMeasurePolicies, if any.
+	RateLimiter *RateLimiter
+	// Policies reads the CounterMeasurePolicies the targets of the actions are checked against, if any.
+	Policies client.Reader
+	// Restriction limits the namespaces of the targets of the actions, if any.
+	Restriction *NamespaceRestriction
 }
 type ActionBuilder func(v1alpha1.Action, ActionContext, bool) Action
 
+// actionClient the client the actions are taken with
+func (c ActionContext) actionClient() client.Client {
+	if c.ActionClient != nil {
+		return c.ActionClient
+	}
+	return c.Client
+}
+
+// newBase creates the base of an action taken with the action context
+func (c ActionContext) newBase(spec v1alpha1.Action, dryRun bool) BaseAction {
+	base := NewBase(c.actionClient(), spec, dryRun)
+	base.Restriction = c.Restriction
+	return base
+}
+
 func NewBase(c client.Client, spec v1alpha1.Action, dryRun bool) BaseAction {
 	return BaseAction{
 		client:       c,
 		Name:         spec.Name,
 		DryRun:       dryRun,
 		RetryEnabled: spec.RetryEnabled,
+		Guard:        spec.Guard,
 	}
 }
 
@@ -73,6 +131,12 @@ func (b *BaseAction) SupportsRetry() bool {
 	return b.RetryEnabled
 }
 
+// checkNamespace checks the target namespace, after evaluating the template (if any), is allowed
+// by the namespace restriction.
+func (b *BaseAction) checkNamespace(ctx context.Context, namespace string, event events.Event) error {
+	return b.Restriction.Allows(ctx, evaluateTemplate(namespace, event))
+}
+
 // createObjectName evaluate the template (if any) in name and namespace to produce an object name.
 func (b *BaseAction) createObjectName(kind, namespace, name string, data events.Event) string {
 	return fmt.Sprintf("%s: '%s/%s'", strings.ToLower(kind),
@@ -83,7 +147,7 @@ func (b *BaseAction) createObjectName(kind, namespace, name string, data events.
 // Initialize registers all the known actions with the registry
 func (r *Registry) Initialize() {
 	r.RegisterAction(v1alpha1.DeleteSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
-		return NewDeleteFromBase(NewBase(c.Client, spec, dryRun), *spec.Delete)
+		return NewDeleteFromBase(c.newBase(spec, dryRun), *spec.Delete)
 	})
 
 	r.RegisterAction(v1alpha1.DebugSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
@@ -92,16 +156,20 @@ func (r *Registry) Initialize() {
 			utilruntime.HandleError(err)
 			panic(fmt.Errorf("not able to create a k8s config for the debug action: %w", err))
 		} else {
-			return NewDebugFromBase(NewBase(c.Client, spec, dryRun), cs.CoreV1(), *spec.Debug)
+			return NewDebugFromBase(c.newBase(spec, dryRun), cs.CoreV1(), *spec.Debug)
 		}
 	})
 
 	r.RegisterAction(v1alpha1.PatchSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
-		return NewPatchFromBase(NewBase(c.Client, spec, dryRun), *spec.Patch)
+		return NewPatchFromBase(c.newBase(spec, dryRun), *spec.Patch)
 	})
 
 	r.RegisterAction(v1alpha1.RestartSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
-		return NewRestartFromBase(NewBase(c.Client, spec, dryRun), *spec.Restart)
+		return NewRestartFromBase(c.newBase(spec, dryRun), *spec.Restart)
+	})
+
+	r.RegisterAction(v1alpha1.WaitForSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
+		return NewWaitForFromBase(c.newBase(spec, dryRun), *spec.WaitFor)
 	})
 }
 
@@ -163,30 +231,65 @@ func evaluateTemplate(templateString string, event events.Event) string {
 	return buf.String()
 }
 
-// Run called with an event when the counter measure actions need to be exeucted.
-func (seq InMemoryRunner) Run(eventCtx ActionContext, event events.Event) {
+// Plan lists the actions that would be taken for an event, with their targets rendered
+// from the event data.
+func (seq InMemoryRunner) Plan(event events.Event) []v1alpha1.PlannedAction {
+	planned := make([]v1alpha1.PlannedAction, 0, len(seq))
+	for _, action := range seq {
+		planned = append(planned, v1alpha1.PlannedAction{
+			Name:   action.GetName(),
+			Type:   action.GetType(),
+			Target: action.GetTargetObjectName(event),
+		})
+	}
+
+	return planned
+}
+
+// Run called with an event when the counter measure actions need to be exeucted, returns
+// the error of the first action that failed.
+func (seq InMemoryRunner) Run(eventCtx ActionContext, event events.Event) error {
 
 	cm := eventCtx.CounterMeasure
 
 	// create a struct that will be used as data for the templates in the custom resource
 	objectMeta := eventCtx.CounterMeasure.ObjectMeta
 	ctx := context.Background()
-	for _, action := range seq {
+
+	for idx, action := range seq {
 		labels := prometheus.Labels{"namespace": objectMeta.Namespace, "type": action.GetType()}
+		result := v1alpha1.ActionResult{
+			Name:      action.GetName(),
+			Type:      action.GetType(),
+			Target:    action.GetTargetObjectName(event),
+			StartTime: &metav1.Time{Time: time.Now()},
+		}
+
+		// the runner is created with an action for each action spec in the same order
+		var spec *v1alpha1.Action
+		if idx < len(cm.Spec.Actions) {
+			spec = &cm.Spec.Actions[idx]
+		}
+
+		if err := eventCtx.checkAction(ctx, spec, action, event); err != nil {
+			recordActionResult(ctx, eventCtx, result, err)
+			return err
+		}
 
 		// Ideally actions are idempotent as retry on error is the default behavior,
 		// but the action spec allows for retries to be disabled.
 		err := retry.OnError(retry.DefaultBackoff, func(err error) bool {
-			return action.SupportsRetry()
+			return action.SupportsRetry() && !IsRefused(err)
 		}, func() error {
 			return action.Perform(ctx, event)
 		})
 
+		recordActionResult(ctx, eventCtx, result, err)
 		if err != nil {
 			metrics.ActionErrors.With(labels).Add(1)
 			eventCtx.Recorder.Event(&cm, "Warning", "ActionError", err.Error())
 			log.Error(err, "action execution error", "name", objectMeta.Name, "namespace", objectMeta.Namespace)
-			break
+			return err
 		}
 
 		metrics.ActionsTaken.With(labels).Add(1)
@@ -198,5 +301,100 @@ func (seq InMemoryRunner) Run(eventCtx ActionContext, event events.Event) {
 		}
 
 		eventCtx.Recorder.Event(&cm, "Normal", "ActionTaken", msg)
+
+		// the revert is recorded as soon as the action is taken, so it isn't lost when a later
+		// action fails or the operator stops before the run completes.
+		if spec != nil && !cm.Spec.DryRun {
+			if revert := newPendingRevert(*spec, action, event); revert != nil {
+				err := addPendingReverts(ctx, eventCtx.Client, client.ObjectKeyFromObject(&cm), []v1alpha1.PendingRevert{*revert})
+				if err != nil {
+					eventCtx.Recorder.Event(&cm, "Warning", "RevertError", err.Error())
+					log.Error(err, "failed to record pending revert", "name", objectMeta.Name, "namespace", objectMeta.Namespace)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// targetNamespace the namespace of the target of the action after evaluating the template, or the
+// namespace of the countermeasure when the action has no target.
+func (c ActionContext) targetNamespace(spec *v1alpha1.Action, event events.Event) string {
+	if spec != nil {
+		if target := spec.TargetRef(); target != nil {
+			return evaluateTemplate(target.Namespace, event)
+		}
+	}
+	return c.CounterMeasure.Namespace
+}
+
+// checkAction checks the target of the action is in a namespace the countermeasure may target and
+// is allowed by the CounterMeasurePolicies, the guard of the action passes and the rate limits aren't
+// exhausted, in that order, before the action is taken. spec is the spec the action was created from, if any.
+func (c ActionContext) checkAction(ctx context.Context, spec *v1alpha1.Action, action Action, event events.Event) error {
+	cm := c.CounterMeasure
+	target := action.GetTargetObjectName(event)
+
+	// the namespace restriction goes first so the guard never reads objects the countermeasure may not
+	// target and the refused actions don't count against the rate limits
+	if spec != nil && spec.TargetRef() != nil {
+		if err := c.Restriction.Allows(ctx, c.targetNamespace(spec, event)); err != nil {
+			if IsRefused(err) {
+				c.Recorder.Event(&cm, "Warning", "NamespaceDenied",
+					fmt.Sprintf("Action '%s' on %s denied: %s", action.GetName(), target, err.Error()))
+			}
+			return err
+		}
+	}
+
+	if c.Policies != nil && spec != nil {
+		if err := checkTargetPolicies(ctx, c.Policies, *spec, event); err != nil {
+			c.Recorder.Event(&cm, "Warning", "PolicyDenied",
+				fmt.Sprintf("Action '%s' on %s denied: %s", action.GetName(), target, err.Error()))
+			return &RefusedError{Reason: "PolicyDenied", Err: err}
+		}
+	}
+
+	if guarded, ok := action.(Guarded); ok {
+		if err := guarded.CheckGuard(ctx, event); err != nil {
+			c.Recorder.Event(&cm, "Warning", "GuardFailed",
+				fmt.Sprintf("Action '%s' on %s aborted: %s", action.GetName(), target, err.Error()))
+			return &RefusedError{Reason: "GuardFailed", Err: err}
+		}
+	}
+
+	// dry runs don't change anything so they don't count against the rate limits
+	if c.RateLimiter != nil && !cm.Spec.DryRun {
+		if err := c.RateLimiter.Allow(ctx, c.targetNamespace(spec, event), action.GetType()); err != nil {
+			metrics.RateLimited.With(prometheus.Labels{"namespace": cm.Namespace, "type": action.GetType()}).Add(1)
+			c.Recorder.Event(&cm, "Warning", "RateLimited",
+				fmt.Sprintf("Action '%s' on %s refused: %s", action.GetName(), target, err.Error()))
+			return &RefusedError{Reason: "RateLimited", Err: err}
+		}
+	}
+
+	return nil
+}
+
+// recordActionResult append the result of an action to the run status, if the actions are
+// being recorded on a run.
+func recordActionResult(ctx context.Context, eventCtx ActionContext, result v1alpha1.ActionResult, err error) {
+	if eventCtx.Run == nil {
+		return
+	}
+
+	result.EndTime = &metav1.Time{Time: time.Now()}
+	result.Outcome = v1alpha1.ActionSucceeded
+	if err != nil {
+		result.Outcome = v1alpha1.ActionFailed
+		result.Error = err.Error()
+	}
+
+	err = UpdateRunStatus(ctx, eventCtx.Client, *eventCtx.Run, func(status *v1alpha1.CounterMeasureRunStatus) {
+		status.Actions = append(status.Actions, result)
+	})
+	if err != nil {
+		log.Error(err, "failed to record action result", "name", eventCtx.Run.Name, "namespace", eventCtx.Run.Namespace)
 	}
 }
@@ -0,0 +1,166 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RunExecutor executes the actions of a CounterMeasureRun that has been approved.
+type RunExecutor interface {
+	ExecuteRun(context.Context, *v1alpha1.CounterMeasureRun) error
+}
+
+var _ RunExecutor = &Manager{}
+
+// requestApproval creates a CounterMeasureRun pending approval for the event, unless
+// there already is one pending for the same event.
+func (m *Manager) requestApproval(cm *v1alpha1.CounterMeasure, evt events.Event) error {
+	ctx := context.Background()
+
+	namespace, err := m.runNamespace(cm)
+	if err != nil {
+		return err
+	}
+
+	runs := &v1alpha1.CounterMeasureRunList{}
+	err = m.client.List(ctx, runs, client.InNamespace(namespace), client.MatchingLabels(runLabels(cm, evt)))
+	if err != nil {
+		return err
+	}
+
+	for _, run := range runs.Items {
+		if run.Status.Phase == v1alpha1.PendingApproval {
+			m.recorder.Event(cm, "Normal", "Skipping",
+				fmt.Sprintf("Event is already waiting for approval in run '%s'.", run.Name))
+			return nil
+		}
+	}
+
+	actionRunner, err := m.ActionRegistry.NewRunner(ActionContext{
+		Client:         m.client,
+		RestConfig:     m.restConfig,
+		Recorder:       m.recorder,
+		CounterMeasure: *cm,
+	})
+	if err != nil {
+		return err
+	}
+
+	expiresAt := &metav1.Time{Time: time.Now().Add(cm.Spec.Approval.Timeout.Duration)}
+	status := v1alpha1.CounterMeasureRunStatus{
+		Phase:   v1alpha1.PendingApproval,
+		Message: fmt.Sprintf("Waiting for approval until %s", expiresAt.Format(time.RFC3339)),
+	}
+
+	run, err := m.createRun(ctx, cm, evt, actionRunner, status, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	m.recorder.Event(cm, "Normal", "ApprovalRequired",
+		fmt.Sprintf("Event '%s' detected, actions are waiting for approval in run '%s'", evt.Name, run.Name))
+	return nil
+}
+
+// ExecuteRun runs the actions of an approved CounterMeasureRun in the background, recording
+// the outcome on the run status. The run is skipped when the countermeasure is still running or
+// suppressed for the event, or its circuit breaker is tripped, as it would be for a new event. The
+// run expires when the actions of the countermeasure changed since the run was approved.
+func (m *Manager) ExecuteRun(ctx context.Context, run *v1alpha1.CounterMeasureRun) error {
+	cmKey := types.NamespacedName{Namespace: run.Namespace, Name: run.Spec.CounterMeasure}
+	if run.Spec.ClusterScoped {
+		cmKey.Namespace = ""
+	}
+
+	cm, err := getCounterMeasure(ctx, m.client, cmKey)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ResolveTemplate(ctx, m.client, cm); err != nil {
+		return err
+	}
+
+	// the run was approved for the dry run mode in effect when it was created
+	cm.Spec.DryRun = run.Spec.DryRun
+
+	key := manager.ToKey(cm.ObjectMeta)
+	evt := FromEventRecord(run.Spec.Event)
+
+	phase, message := v1alpha1.Running, ""
+	if !equality.Semantic.DeepEqual(cm.Spec.Actions, run.Spec.Actions) {
+		phase, message = v1alpha1.Expired, "The actions of the countermeasure changed since the run was created."
+	} else if entry := m.state.GetCounterMeasure(key); entry != nil && entry.IsSuppressed(evt, m.targets(cm, evt)...) {
+		phase, message = v1alpha1.Skipped, "Previous execution is still in progress or suppressed."
+	} else if cm.Spec.CircuitBreaker != nil && m.isTripped(ctx, key.NamespacedName) {
+		phase, message = v1alpha1.Skipped, "Circuit breaker is tripped."
+	}
+
+	runKey := client.ObjectKeyFromObject(run)
+	claimed, err := m.claimApprovedRun(ctx, runKey, phase, message)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if phase != v1alpha1.Running {
+		if phase == v1alpha1.Expired {
+			m.recorder.Event(cm, "Warning", "Expired", message)
+		} else {
+			m.recorder.Event(cm, "Normal", "Skipping", message)
+		}
+		if err := m.pruneRuns(ctx, cm); err != nil {
+			managerLog.Error(err, "failed to prune countermeasure runs", "name", cm.Name, "namespace", cm.Namespace)
+		}
+		return nil
+	}
+
+	go m.execute(key, cm, evt, &runKey)
+
+	return nil
+}
+
+// claimApprovedRun moves the run out of PendingApproval into the phase, if it's still pending approval
+// and approved when read back from the API server, so a run is never executed twice nor after it
+// expired or its approval was withdrawn. Returns false when the run was left as is.
+func (m *Manager) claimApprovedRun(ctx context.Context, key types.NamespacedName, phase v1alpha1.RunPhase, message string) (bool, error) {
+	claimed := false
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		claimed = false
+		run := &v1alpha1.CounterMeasureRun{}
+		if err := m.client.Get(ctx, key, run); err != nil {
+			return err
+		}
+
+		if run.Status.Phase != v1alpha1.PendingApproval || !run.IsApproved() {
+			return nil
+		}
+
+		now := &metav1.Time{Time: time.Now()}
+		run.Status.Phase = phase
+		run.Status.Message = message
+		if phase == v1alpha1.Running {
+			run.Status.StartTime = now
+		} else {
+			run.Status.CompletionTime = now
+		}
+
+		// the update is rejected with a conflict if the run changed since it was read
+		if err := m.client.Status().Update(ctx, run); err != nil {
+			return err
+		}
+		claimed = true
+		return nil
+	})
+
+	return claimed, err
+}
@@ -0,0 +1,271 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/actions/state"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestManager_Approval(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("approval"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			OnEvent: v1alpha1.OnEventSpec{
+				EventName: "event1",
+			},
+			Approval: &v1alpha1.ApprovalSpec{
+				Timeout: metav1.Duration{Duration: time.Hour},
+			},
+			Actions: []v1alpha1.Action{
+				{
+					Name: "restart",
+					Restart: &v1alpha1.RestartSpec{
+						DeploymentRef: v1alpha1.DeploymentReference{
+							Namespace: "ns",
+							Name:      "{{ .Data.deployment }}",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s := runtime.NewScheme()
+	clientgoscheme.AddToScheme(s)
+	v1alpha1.AddToScheme(s)
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(cm.DeepCopy()).Build()
+
+	performed := make(chan struct{}, 1)
+	registry := Registry{}
+	registry.RegisterAction(v1alpha1.RestartSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
+		return &mockPerformAction{
+			BaseAction: NewBase(c.Client, spec, dryRun),
+			spec:       *spec.Restart,
+			performed:  performed,
+		}
+	})
+
+	mgr := &Manager{
+		client:         k8sClient,
+		recorder:       record.NewFakeRecorder(10),
+		ActionRegistry: registry,
+		state:          state.NewState(),
+	}
+
+	event := events.Event{
+		Name: "event1",
+		Data: &events.EventData{"deployment": "app"},
+	}
+
+	// the event should create a single pending run, even when detected twice
+	assert.NoError(t, mgr.requestApproval(cm, event))
+	assert.NoError(t, mgr.requestApproval(cm, event))
+
+	runs := &v1alpha1.CounterMeasureRunList{}
+	assert.NoError(t, k8sClient.List(context.TODO(), runs, client.InNamespace(cm.Namespace)))
+	assert.Equal(t, 1, len(runs.Items))
+
+	run := runs.Items[0]
+	assert.Equal(t, v1alpha1.PendingApproval, run.Status.Phase)
+	assert.Equal(t, cm.Name, run.Spec.CounterMeasure)
+	assert.Equal(t, cm.Name, run.OwnerReferences[0].Name)
+	assert.Equal(t, []v1alpha1.PlannedAction{
+		{Name: "restart", Type: "restart", Target: "deployment: 'ns/app'"},
+	}, run.Spec.PlannedActions)
+	assert.True(t, run.Spec.ExpiresAt.After(time.Now().Add(59*time.Minute)))
+	assert.False(t, run.IsApproved())
+
+	// nothing should have been performed before the approval
+	assert.Equal(t, 0, len(performed))
+
+	// the approval is read back from the API server
+	approved := run.DeepCopy()
+	approved.Annotations = map[string]string{v1alpha1.ApprovedAnnotation: "true"}
+	assert.True(t, approved.IsApproved())
+	assert.NoError(t, mgr.ExecuteRun(context.TODO(), approved))
+	assert.Equal(t, 0, len(performed))
+
+	assert.NoError(t, k8sClient.Update(context.TODO(), approved))
+	assert.NoError(t, mgr.ExecuteRun(context.TODO(), &run))
+
+	assert.Eventually(t, func() bool {
+		updated := &v1alpha1.CounterMeasureRun{}
+		if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(&run), updated); err != nil {
+			return false
+		}
+		return updated.Status.Phase == v1alpha1.Succeeded && len(updated.Status.Actions) == 1
+	}, time.Second*5, time.Millisecond*100, "expected the run to succeed")
+	assert.Equal(t, 1, len(performed))
+
+	// the run is no longer pending approval so it's not executed again
+	assert.NoError(t, mgr.ExecuteRun(context.TODO(), &run))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, len(performed))
+}
+
+func TestManager_ExecuteRunSuppressed(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("approval"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			OnEvent: v1alpha1.OnEventSpec{
+				EventName: "event1",
+			},
+			Approval: &v1alpha1.ApprovalSpec{
+				Timeout: metav1.Duration{Duration: time.Hour},
+			},
+			Actions: []v1alpha1.Action{
+				{
+					Name:    "restart",
+					Restart: &v1alpha1.RestartSpec{DeploymentRef: v1alpha1.DeploymentReference{Namespace: "ns", Name: "app"}},
+				},
+			},
+		},
+	}
+
+	s := runtime.NewScheme()
+	clientgoscheme.AddToScheme(s)
+	v1alpha1.AddToScheme(s)
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(cm.DeepCopy()).Build()
+
+	performed := make(chan struct{}, 1)
+	registry := Registry{}
+	registry.RegisterAction(v1alpha1.RestartSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
+		return &mockPerformAction{
+			BaseAction: NewBase(c.Client, spec, dryRun),
+			spec:       *spec.Restart,
+			performed:  performed,
+		}
+	})
+
+	mgr := &Manager{
+		client:         k8sClient,
+		recorder:       record.NewFakeRecorder(10),
+		ActionRegistry: registry,
+		state:          state.NewState(),
+	}
+
+	deployed := &v1alpha1.CounterMeasure{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cm), deployed))
+	assert.NoError(t, mgr.state.Add(deployed))
+
+	event := events.Event{Name: "event1", Data: &events.EventData{}}
+	assert.NoError(t, mgr.requestApproval(deployed, event))
+
+	runs := &v1alpha1.CounterMeasureRunList{}
+	assert.NoError(t, k8sClient.List(context.TODO(), runs, client.InNamespace(cm.Namespace)))
+	assert.Equal(t, 1, len(runs.Items))
+
+	run := &runs.Items[0]
+	run.Spec.Approved = true
+	assert.NoError(t, k8sClient.Update(context.TODO(), run))
+
+	// the countermeasure is running for another event when the run is approved
+	key := manager.ToKey(deployed.ObjectMeta)
+	mgr.state.CounterMeasureStart(events.Event{Name: "event1"}, key)
+	assert.NoError(t, mgr.ExecuteRun(context.TODO(), run))
+
+	updated := &v1alpha1.CounterMeasureRun{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(run), updated))
+	assert.Equal(t, v1alpha1.Skipped, updated.Status.Phase)
+	assert.NotNil(t, updated.Status.CompletionTime)
+	assert.Equal(t, 0, len(performed))
+}
+
+func TestManager_ExecuteRunChanged(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("approval"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			OnEvent: v1alpha1.OnEventSpec{
+				EventName: "event1",
+			},
+			Approval: &v1alpha1.ApprovalSpec{
+				Timeout: metav1.Duration{Duration: time.Hour},
+			},
+			Actions: []v1alpha1.Action{
+				{
+					Name:    "restart",
+					Restart: &v1alpha1.RestartSpec{DeploymentRef: v1alpha1.DeploymentReference{Namespace: "ns", Name: "app"}},
+				},
+			},
+		},
+	}
+
+	s := runtime.NewScheme()
+	clientgoscheme.AddToScheme(s)
+	v1alpha1.AddToScheme(s)
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(cm.DeepCopy()).Build()
+
+	performed := make(chan struct{}, 1)
+	registry := Registry{}
+	registry.RegisterAction(v1alpha1.RestartSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
+		return &mockPerformAction{
+			BaseAction: NewBase(c.Client, spec, dryRun),
+			spec:       *spec.Restart,
+			performed:  performed,
+		}
+	})
+
+	mgr := &Manager{
+		client:         k8sClient,
+		recorder:       record.NewFakeRecorder(10),
+		ActionRegistry: registry,
+		state:          state.NewState(),
+	}
+
+	event := events.Event{Name: "event1", Data: &events.EventData{}}
+	assert.NoError(t, mgr.requestApproval(cm, event))
+
+	runs := &v1alpha1.CounterMeasureRunList{}
+	assert.NoError(t, k8sClient.List(context.TODO(), runs, client.InNamespace(cm.Namespace)))
+	assert.Equal(t, 1, len(runs.Items))
+	assert.Equal(t, cm.Spec.Actions, runs.Items[0].Spec.Actions)
+
+	run := &runs.Items[0]
+	run.Spec.Approved = true
+	assert.NoError(t, k8sClient.Update(context.TODO(), run))
+
+	// the countermeasure targets another deployment after the run was created
+	changed := &v1alpha1.CounterMeasure{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cm), changed))
+	changed.Spec.Actions[0].Restart.DeploymentRef.Name = "other"
+	assert.NoError(t, k8sClient.Update(context.TODO(), changed))
+
+	assert.NoError(t, mgr.ExecuteRun(context.TODO(), run))
+
+	updated := &v1alpha1.CounterMeasureRun{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(run), updated))
+	assert.Equal(t, v1alpha1.Expired, updated.Status.Phase)
+	assert.NotNil(t, updated.Status.CompletionTime)
+	assert.Equal(t, 0, len(performed))
+}
+
+type mockPerformAction struct {
+	BaseAction
+	spec      v1alpha1.RestartSpec
+	performed chan struct{}
+}
+
+func (m *mockPerformAction) Perform(context.Context, events.Event) error {
+	m.performed <- struct{}{}
+	return nil
+}
+
+func (m *mockPerformAction) GetType() string {
+	return "restart"
+}
+
+func (m *mockPerformAction) GetTargetObjectName(event events.Event) string {
+	return m.createObjectName("deployment", m.spec.DeploymentRef.Namespace, m.spec.DeploymentRef.Name, event)
+}
@@ -0,0 +1,155 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// recordFailure counts a failed or ineffective execution against the circuit breaker of the
+// countermeasure, tripping the breaker when there are too many consecutive failures in the window.
+func (m *Manager) recordFailure(ctx context.Context, cm *v1alpha1.CounterMeasure) {
+	breaker := cm.Spec.CircuitBreaker
+	if breaker == nil {
+		return
+	}
+
+	tripped := false
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest, err := getCounterMeasure(ctx, m.client, client.ObjectKeyFromObject(cm))
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		since := now.Add(-breaker.Window.Duration)
+		failures := make([]metav1.Time, 0, len(latest.Status.RecentFailures)+1)
+		for _, failure := range latest.Status.RecentFailures {
+			if failure.After(since) {
+				failures = append(failures, failure)
+			}
+		}
+		latest.Status.RecentFailures = append(failures, metav1.Time{Time: now})
+
+		tripped = false
+		if len(latest.Status.RecentFailures) >= int(breaker.MaxFailures) &&
+			!meta.IsStatusConditionTrue(latest.Status.Conditions, v1alpha1.TypeTripped) {
+			meta.SetStatusCondition(&latest.Status.Conditions, metav1.Condition{
+				Type:               v1alpha1.TypeTripped,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: latest.Generation,
+				Reason:             v1alpha1.ReasonTooManyFailures,
+				Message: fmt.Sprintf("%d failures within %s", len(latest.Status.RecentFailures),
+					breaker.Window.Duration),
+			})
+			tripped = true
+		}
+
+		return updateCounterMeasureStatus(ctx, m.client, latest)
+	})
+
+	if err != nil {
+		managerLog.Error(err, "failed to record countermeasure failure", "name", cm.Name, "namespace", cm.Namespace)
+		return
+	}
+
+	if tripped {
+		m.recorder.Event(cm, "Warning", v1alpha1.TypeTripped,
+			fmt.Sprintf("Circuit breaker tripped after %d failures within %s, the countermeasure is stopped until it's reset",
+				breaker.MaxFailures, breaker.Window.Duration))
+	}
+}
+
+// recordSuccess clears the failures counted by the circuit breaker of the countermeasure, so
+// only consecutive failures trip the breaker.
+func (m *Manager) recordSuccess(ctx context.Context, cm *v1alpha1.CounterMeasure) {
+	if cm.Spec.CircuitBreaker == nil {
+		return
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest, err := getCounterMeasure(ctx, m.client, client.ObjectKeyFromObject(cm))
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		if len(latest.Status.RecentFailures) == 0 {
+			return nil
+		}
+
+		latest.Status.RecentFailures = nil
+		return updateCounterMeasureStatus(ctx, m.client, latest)
+	})
+
+	if err != nil {
+		managerLog.Error(err, "failed to record countermeasure success", "name", cm.Name, "namespace", cm.Namespace)
+	}
+}
+
+// isTripped checks if the circuit breaker of the countermeasure is tripped, resetting the
+// breaker once the cooldown has elapsed.
+func (m *Manager) isTripped(ctx context.Context, key types.NamespacedName) bool {
+	cm, err := getCounterMeasure(ctx, m.client, key)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			managerLog.Error(err, "failed to check countermeasure circuit breaker", "name", key.Name, "namespace", key.Namespace)
+		}
+		return false
+	}
+
+	condition := meta.FindStatusCondition(cm.Status.Conditions, v1alpha1.TypeTripped)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return false
+	}
+
+	breaker := cm.Spec.CircuitBreaker
+	if breaker == nil {
+		// the breaker was removed from the spec
+		return false
+	}
+
+	if breaker.Cooldown != nil && time.Since(condition.LastTransitionTime.Time) >= breaker.Cooldown.Duration {
+		err := ResetCircuitBreaker(ctx, m.client, key, v1alpha1.ReasonCooledDown, "Cooldown elapsed")
+		if err != nil {
+			managerLog.Error(err, "failed to reset countermeasure circuit breaker", "name", key.Name, "namespace", key.Namespace)
+			return true
+		}
+
+		m.recorder.Event(cm, "Normal", v1alpha1.ReasonCooledDown, "Circuit breaker reset after the cooldown")
+		return false
+	}
+
+	return true
+}
+
+// ResetCircuitBreaker clears the failures counted by the circuit breaker and marks it as not tripped.
+func ResetCircuitBreaker(ctx context.Context, c client.Client, key types.NamespacedName, reason, message string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		cm, err := getCounterMeasure(ctx, c, key)
+		if err != nil {
+			return err
+		}
+
+		cm.Status.RecentFailures = nil
+		meta.SetStatusCondition(&cm.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.TypeTripped,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: cm.Generation,
+			Reason:             reason,
+			Message:            message,
+		})
+
+		return updateCounterMeasureStatus(ctx, c, cm)
+	})
+}
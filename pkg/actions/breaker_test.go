@@ -0,0 +1,215 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/actions/state"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestManager_CircuitBreaker(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("breaker"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			CircuitBreaker: &v1alpha1.CircuitBreakerSpec{
+				MaxFailures: 2,
+				Window:      metav1.Duration{Duration: time.Hour},
+			},
+		},
+		Status: v1alpha1.CounterMeasureStatus{
+			// failures outside the window are not counted
+			RecentFailures: []metav1.Time{{Time: time.Now().Add(-2 * time.Hour)}},
+		},
+	}
+
+	k8sClient := newRunsClient(cm.DeepCopy())
+	recorder := record.NewFakeRecorder(10)
+	mgr := &Manager{
+		client:   k8sClient,
+		recorder: recorder,
+	}
+
+	ctx := context.TODO()
+	key := client.ObjectKeyFromObject(cm)
+
+	mgr.recordFailure(ctx, cm)
+	assert.False(t, mgr.isTripped(ctx, key))
+
+	mgr.recordFailure(ctx, cm)
+	assert.True(t, mgr.isTripped(ctx, key))
+	assert.Contains(t, <-recorder.Events, v1alpha1.TypeTripped)
+
+	updated := &v1alpha1.CounterMeasure{}
+	assert.NoError(t, k8sClient.Get(ctx, key, updated))
+	assert.Equal(t, 2, len(updated.Status.RecentFailures))
+
+	assert.NoError(t, ResetCircuitBreaker(ctx, k8sClient, key, v1alpha1.ReasonReset, "reset"))
+	assert.False(t, mgr.isTripped(ctx, key))
+
+	assert.NoError(t, k8sClient.Get(ctx, key, updated))
+	assert.Empty(t, updated.Status.RecentFailures)
+}
+
+func TestManager_CircuitBreakerIgnoresRefusals(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("refused"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			OnEvent: v1alpha1.OnEventSpec{EventName: "event1"},
+			CircuitBreaker: &v1alpha1.CircuitBreakerSpec{
+				MaxFailures: 1,
+				Window:      metav1.Duration{Duration: time.Hour},
+			},
+			Actions: []v1alpha1.Action{
+				{
+					Name: "restart",
+					Restart: &v1alpha1.RestartSpec{
+						DeploymentRef: v1alpha1.DeploymentReference{Namespace: "{{ .Data.namespace }}", Name: "app"},
+					},
+				},
+			},
+		},
+	}
+
+	k8sClient := newRunsClient(cm.DeepCopy())
+	registry := Registry{}
+	registry.Initialize()
+	mgr := &Manager{
+		client:         k8sClient,
+		recorder:       record.NewFakeRecorder(10),
+		ActionRegistry: registry,
+		state:          state.NewState(),
+	}
+
+	ctx := context.TODO()
+	key := client.ObjectKeyFromObject(cm)
+	assert.NoError(t, mgr.state.Add(cm.DeepCopy()))
+
+	// the namespace restriction refuses the target, which doesn't count as a failure
+	mgr.execute(manager.ToKey(cm.ObjectMeta), cm, events.Event{
+		Name: "event1",
+		Data: &events.EventData{"namespace": "other"},
+	}, nil)
+	assert.False(t, mgr.isTripped(ctx, key))
+
+	// the deployment doesn't exist so the restart fails
+	mgr.execute(manager.ToKey(cm.ObjectMeta), cm, events.Event{
+		Name: "event1",
+		Data: &events.EventData{"namespace": cm.Namespace},
+	}, nil)
+	assert.True(t, mgr.isTripped(ctx, key))
+}
+
+func TestManager_CircuitBreakerConsecutiveFailures(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("consecutive"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			OnEvent: v1alpha1.OnEventSpec{EventName: "event1"},
+			CircuitBreaker: &v1alpha1.CircuitBreakerSpec{
+				MaxFailures: 2,
+				Window:      metav1.Duration{Duration: time.Hour},
+			},
+			Actions: []v1alpha1.Action{
+				{
+					Name:    "restart",
+					Restart: &v1alpha1.RestartSpec{DeploymentRef: v1alpha1.DeploymentReference{Namespace: "ns", Name: "app"}},
+				},
+			},
+		},
+	}
+
+	k8sClient := newRunsClient(cm.DeepCopy())
+	registry := Registry{}
+	registry.RegisterAction(v1alpha1.RestartSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
+		return &outcomeAction{}
+	})
+	mgr := &Manager{
+		client:         k8sClient,
+		recorder:       record.NewFakeRecorder(10),
+		ActionRegistry: registry,
+		state:          state.NewState(),
+	}
+
+	ctx := context.TODO()
+	key := client.ObjectKeyFromObject(cm)
+	assert.NoError(t, mgr.state.Add(cm.DeepCopy()))
+
+	execute := func(fail string) {
+		mgr.execute(manager.ToKey(cm.ObjectMeta), cm, events.Event{
+			Name: "event1",
+			Data: &events.EventData{"fail": fail},
+		}, nil)
+	}
+
+	// the success in between clears the first failure
+	execute("true")
+	execute("false")
+	execute("true")
+	assert.False(t, mgr.isTripped(ctx, key))
+
+	updated := &v1alpha1.CounterMeasure{}
+	assert.NoError(t, k8sClient.Get(ctx, key, updated))
+	assert.Equal(t, 1, len(updated.Status.RecentFailures))
+
+	execute("true")
+	assert.True(t, mgr.isTripped(ctx, key))
+}
+
+func TestManager_CircuitBreakerCooldown(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("cooldown"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			CircuitBreaker: &v1alpha1.CircuitBreakerSpec{
+				MaxFailures: 1,
+				Window:      metav1.Duration{Duration: time.Hour},
+				Cooldown:    &metav1.Duration{Duration: time.Minute},
+			},
+		},
+		Status: v1alpha1.CounterMeasureStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:               v1alpha1.TypeTripped,
+					Status:             metav1.ConditionTrue,
+					Reason:             v1alpha1.ReasonTooManyFailures,
+					LastTransitionTime: metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+				},
+			},
+		},
+	}
+
+	k8sClient := newRunsClient(cm.DeepCopy())
+	mgr := &Manager{
+		client:   k8sClient,
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	ctx := context.TODO()
+	key := client.ObjectKeyFromObject(cm)
+	assert.False(t, mgr.isTripped(ctx, key))
+
+	updated := &v1alpha1.CounterMeasure{}
+	assert.NoError(t, k8sClient.Get(ctx, key, updated))
+	condition := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.TypeTripped)
+	assert.Equal(t, v1alpha1.ReasonCooledDown, condition.Reason)
+}
+
+// outcomeAction fails when the event has fail=true
+type outcomeAction struct {
+	MockAction
+}
+
+func (a *outcomeAction) Perform(_ context.Context, event events.Event) error {
+	if event.Data.Get("fail") == "true" {
+		return fmt.Errorf("failed")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterCounterMeasures are run by the manager as CounterMeasures with no namespace, the helpers
+// in this file read and write the right kind of object for either.
+
+// isClusterScoped checks if the countermeasure is a ClusterCounterMeasure
+func isClusterScoped(cm *v1alpha1.CounterMeasure) bool {
+	return cm.Namespace == ""
+}
+
+// getCounterMeasure reads the countermeasure, or the ClusterCounterMeasure when the key has no namespace.
+func getCounterMeasure(ctx context.Context, c client.Reader, key types.NamespacedName) (*v1alpha1.CounterMeasure, error) {
+	if key.Namespace != "" {
+		cm := &v1alpha1.CounterMeasure{}
+		if err := c.Get(ctx, key, cm); err != nil {
+			return nil, err
+		}
+		return cm, nil
+	}
+
+	ccm := &v1alpha1.ClusterCounterMeasure{}
+	if err := c.Get(ctx, key, ccm); err != nil {
+		return nil, err
+	}
+	return ccm.AsCounterMeasure(), nil
+}
+
+// updateCounterMeasureStatus updates the status of the countermeasure, or of the ClusterCounterMeasure
+// when it has no namespace.
+func updateCounterMeasureStatus(ctx context.Context, c client.Client, cm *v1alpha1.CounterMeasure) error {
+	if !isClusterScoped(cm) {
+		return c.Status().Update(ctx, cm)
+	}
+
+	ccm := &v1alpha1.ClusterCounterMeasure{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), ccm); err != nil {
+		return err
+	}
+
+	// keep the version that was read so conflicting updates are still detected
+	ccm.ResourceVersion = cm.ResourceVersion
+	ccm.Status = cm.Status
+	return c.Status().Update(ctx, ccm)
+}
+
+// runNamespace the namespace the runs of the countermeasure are created in.
+func (m *Manager) runNamespace(cm *v1alpha1.CounterMeasure) (string, error) {
+	if !isClusterScoped(cm) {
+		return cm.Namespace, nil
+	}
+
+	if m.ClusterRunNamespace == "" {
+		return "", fmt.Errorf("no namespace configured for the runs of cluster countermeasure '%s'", cm.Name)
+	}
+	return m.ClusterRunNamespace, nil
+}
+
+// counterMeasureLabel the label naming the countermeasure on its runs
+func counterMeasureLabel(cm *v1alpha1.CounterMeasure) string {
+	if isClusterScoped(cm) {
+		return v1alpha1.ClusterCounterMeasureLabel
+	}
+	return v1alpha1.CounterMeasureLabel
+}
+
+// setRunOwner makes the countermeasure the controller of the run. The owner is set explicitly since the
+// scheme would resolve the kind of a ClusterCounterMeasure run as a CounterMeasure to CounterMeasure.
+func setRunOwner(cm *v1alpha1.CounterMeasure, run *v1alpha1.CounterMeasureRun) {
+	kind := "CounterMeasure"
+	if isClusterScoped(cm) {
+		kind = "ClusterCounterMeasure"
+	}
+
+	run.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(cm, v1alpha1.GroupVersion.WithKind(kind)),
+	}
+}
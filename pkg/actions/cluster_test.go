@@ -0,0 +1,134 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/actions/state"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestManager_ExecuteClusterCounterMeasure(t *testing.T) {
+	ccm := &v1alpha1.ClusterCounterMeasure{
+		ObjectMeta: metav1.ObjectMeta{Name: "auto-heal", Generation: 1, UID: "ccm-uid"},
+		Spec: v1alpha1.ClusterCounterMeasureSpec{
+			CounterMeasureSpec: v1alpha1.CounterMeasureSpec{
+				OnEvent: v1alpha1.OnEventSpec{
+					EventName: "event1",
+					SuppressionPolicy: &v1alpha1.SuppressionPolicySpec{
+						Duration: &metav1.Duration{Duration: time.Minute},
+					},
+				},
+				Actions: []v1alpha1.Action{
+					{
+						Name: "delete",
+						Delete: &v1alpha1.DeleteSpec{
+							TargetObjectRef: v1alpha1.ObjectReference{
+								Namespace:  "{{ .Data.namespace }}",
+								Name:       "{{ .Data.pod }}",
+								Kind:       "Pod",
+								ApiVersion: "v1",
+							},
+						},
+					},
+				},
+			},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"auto-heal": "true"},
+			},
+		},
+	}
+
+	healed := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "healed", Labels: map[string]string{"auto-heal": "true"}}}
+	other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+	pods := []client.Object{
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "crashing", Namespace: "healed", Labels: map[string]string{"app": "test-app"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "crashing", Namespace: "other", Labels: map[string]string{"app": "test-app"}}},
+	}
+
+	k8sClient := newRunsClient(append(pods, ccm.DeepCopy(), healed, other)...)
+
+	registry := Registry{}
+	registry.Initialize()
+	mgr := &Manager{
+		client:              k8sClient,
+		recorder:            record.NewFakeRecorder(10),
+		ActionRegistry:      registry,
+		state:               state.NewState(),
+		ClusterRunNamespace: "operator",
+	}
+
+	cm := ccm.AsCounterMeasure()
+	assert.NoError(t, mgr.state.Add(cm.DeepCopy()))
+	key := manager.ToKey(cm.ObjectMeta)
+
+	for _, namespace := range []string{"healed", "other"} {
+		mgr.execute(key, cm, events.Event{
+			Name: "event1",
+			Data: &events.EventData{"namespace": namespace, "pod": "crashing"},
+		}, nil)
+	}
+
+	// only the pod in the selected namespace was deleted
+	assertPodExists(t, k8sClient, 1)
+	remaining := &corev1.PodList{}
+	assert.NoError(t, k8sClient.List(context.TODO(), remaining))
+	assert.Equal(t, "other", remaining.Items[0].Namespace)
+
+	// the runs are recorded in the cluster run namespace
+	runs := &v1alpha1.CounterMeasureRunList{}
+	assert.NoError(t, k8sClient.List(context.TODO(), runs, client.InNamespace("operator"),
+		client.MatchingLabels{v1alpha1.ClusterCounterMeasureLabel: ccm.Name}))
+	assert.Equal(t, 2, len(runs.Items))
+	for _, run := range runs.Items {
+		assert.True(t, run.Spec.ClusterScoped)
+		assert.Equal(t, "ClusterCounterMeasure", run.OwnerReferences[0].Kind)
+		assert.Equal(t, ccm.UID, run.OwnerReferences[0].UID)
+	}
+
+	// the suppressions are persisted on the cluster countermeasure
+	updated := &v1alpha1.ClusterCounterMeasure{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(ccm), updated))
+	assert.Equal(t, 2, len(updated.Status.Suppressions))
+	assert.Equal(t, ccm.Spec.NamespaceSelector, updated.Spec.NamespaceSelector)
+}
+
+func TestManager_ClusterNamespaceRestriction(t *testing.T) {
+	ccm := &v1alpha1.ClusterCounterMeasure{
+		ObjectMeta: metav1.ObjectMeta{Name: "auto-heal"},
+	}
+	healed := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "healed", Labels: map[string]string{"auto-heal": "true"}}}
+
+	k8sClient := newRunsClient(ccm.DeepCopy(), healed)
+	mgr := &Manager{client: k8sClient}
+	ctx := context.TODO()
+
+	// without a selector only cluster scoped objects can be targeted
+	restriction, err := mgr.namespaceRestriction(ctx, ccm.AsCounterMeasure())
+	assert.NoError(t, err)
+	assert.NoError(t, restriction.Allows(ctx, ""))
+	assert.Error(t, restriction.Allows(ctx, "healed"))
+
+	// unless cross namespace targets are allowed
+	mgr.AllowCrossNamespaceTargets = true
+	restriction, err = mgr.namespaceRestriction(ctx, ccm.AsCounterMeasure())
+	assert.NoError(t, err)
+	assert.NoError(t, restriction.Allows(ctx, "healed"))
+
+	// an empty selector selects every namespace
+	mgr.AllowCrossNamespaceTargets = false
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(ccm), ccm))
+	ccm.Spec.NamespaceSelector = &metav1.LabelSelector{}
+	assert.NoError(t, k8sClient.Update(ctx, ccm))
+	restriction, err = mgr.namespaceRestriction(ctx, ccm.AsCounterMeasure())
+	assert.NoError(t, err)
+	assert.NoError(t, restriction.Allows(ctx, "healed"))
+}
@@ -47,6 +47,10 @@ func (d *Debug) GetTargetObjectName(event events.Event) string {
 
 func (d *Debug) Perform(ctx context.Context, event events.Event) error {
 	targetPod := d.spec.PodRef
+	if err := d.checkNamespace(ctx, targetPod.Namespace, event); err != nil {
+		return err
+	}
+
 	podName := ObjectKeyFromTemplate(targetPod.Namespace, targetPod.Name, event)
 	targetContainerName := evaluateTemplate(targetPod.Container, event)
 
@@ -44,6 +44,10 @@ func (d *Delete) Perform(ctx context.Context, event events.Event) error {
 		return err
 	}
 
+	if err := d.checkNamespace(ctx, target.Namespace, event); err != nil {
+		return err
+	}
+
 	object := &unstructured.Unstructured{}
 	object.SetGroupVersionKind(gvk)
 	objectName := ObjectKeyFromTemplate(target.Namespace, target.Name, event)
@@ -0,0 +1,231 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Guarded is implemented by actions that check the blast radius of the action before it's taken.
+type Guarded interface {
+	CheckGuard(context.Context, events.Event) error
+}
+
+var _ Guarded = &Delete{}
+var _ Guarded = &Restart{}
+var _ Guarded = &Patch{}
+var _ Guarded = &Debug{}
+
+// workloads the kinds whose pods can be restarted by a patch, the minimum of ready pods is checked
+// against their replicas.
+var workloads = map[schema.GroupKind]bool{
+	appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind():  true,
+	appsv1.SchemeGroupVersion.WithKind("StatefulSet").GroupKind(): true,
+	appsv1.SchemeGroupVersion.WithKind("ReplicaSet").GroupKind():  true,
+}
+
+// CheckGuard refuses to delete protected objects, or pods when too few pods of the same
+// workload would remain ready.
+func (d *Delete) CheckGuard(ctx context.Context, event events.Event) error {
+	target := d.spec.TargetObjectRef
+	gvk, err := target.ToGroupVersionKind()
+	if err != nil {
+		return err
+	}
+
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	err = d.client.Get(ctx, ObjectKeyFromTemplate(target.Namespace, target.Name, event), object)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// nothing will be deleted
+			return nil
+		}
+		return err
+	}
+
+	if err := checkProtected(object); err != nil {
+		return err
+	}
+
+	if d.Guard == nil || d.Guard.MinReadyPercent == nil || gvk.GroupKind() != corev1.SchemeGroupVersion.WithKind("Pod").GroupKind() {
+		return nil
+	}
+
+	pod := &corev1.Pod{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, pod); err != nil {
+		return err
+	}
+
+	return checkPodsReady(ctx, d.client, pod, *d.Guard.MinReadyPercent)
+}
+
+// CheckGuard refuses to restart protected deployments, or deployments with too few pods ready.
+func (r *Restart) CheckGuard(ctx context.Context, event events.Event) error {
+	target := r.spec.DeploymentRef
+	deployment := &appsv1.Deployment{}
+	err := r.client.Get(ctx, ObjectKeyFromTemplate(target.Namespace, target.Name, event), deployment)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// the restart will fail on its own
+			return nil
+		}
+		return err
+	}
+
+	if err := checkProtected(deployment); err != nil {
+		return err
+	}
+
+	if r.Guard == nil || r.Guard.MinReadyPercent == nil {
+		return nil
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	return checkReplicasReady(deployment, "deployment", deployment.Status.ReadyReplicas, replicas, *r.Guard.MinReadyPercent)
+}
+
+// CheckGuard refuses to patch protected objects, or workloads with too few pods ready.
+func (p *Patch) CheckGuard(ctx context.Context, event events.Event) error {
+	target := p.spec.TargetObjectRef
+	gvk, err := target.ToGroupVersionKind()
+	if err != nil {
+		return err
+	}
+
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	err = p.client.Get(ctx, ObjectKeyFromTemplate(target.Namespace, target.Name, event), object)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// the patch will fail on its own
+			return nil
+		}
+		return err
+	}
+
+	if err := checkProtected(object); err != nil {
+		return err
+	}
+
+	if p.Guard == nil || p.Guard.MinReadyPercent == nil || !workloads[gvk.GroupKind()] {
+		return nil
+	}
+
+	replicas, found, err := unstructured.NestedInt64(object.Object, "spec", "replicas")
+	if err != nil {
+		return err
+	}
+	if !found {
+		replicas = 1
+	}
+
+	ready, _, err := unstructured.NestedInt64(object.Object, "status", "readyReplicas")
+	if err != nil {
+		return err
+	}
+
+	return checkReplicasReady(object, strings.ToLower(gvk.Kind), int32(ready), int32(replicas), *p.Guard.MinReadyPercent)
+}
+
+// CheckGuard refuses to debug protected pods.
+func (d *Debug) CheckGuard(ctx context.Context, event events.Event) error {
+	target := d.spec.PodRef
+	pod := &corev1.Pod{}
+	err := d.client.Get(ctx, ObjectKeyFromTemplate(target.Namespace, target.Name, event), pod)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// the debug will fail on its own
+			return nil
+		}
+		return err
+	}
+
+	return checkProtected(pod)
+}
+
+// checkReplicasReady returns an error if less than the minimum percentage of the replicas of the
+// workload are ready.
+func checkReplicasReady(object metav1.Object, kind string, ready, replicas, minReadyPercent int32) error {
+	if !hasMinReady(ready, replicas, minReadyPercent) {
+		return fmt.Errorf("%s '%s/%s' has %d of %d pods ready, below the minimum of %d%%",
+			kind, object.GetNamespace(), object.GetName(), ready, replicas, minReadyPercent)
+	}
+
+	return nil
+}
+
+// checkProtected returns an error if the object is labeled as protected.
+func checkProtected(object metav1.Object) error {
+	if object.GetLabels()[v1alpha1.ProtectedLabel] == "true" {
+		return fmt.Errorf("'%s/%s' is protected with the label %s=true",
+			object.GetNamespace(), object.GetName(), v1alpha1.ProtectedLabel)
+	}
+	return nil
+}
+
+// checkPodsReady returns an error if removing the pod would leave less than the minimum
+// percentage of the pods owned by the same controller ready.
+func checkPodsReady(ctx context.Context, c client.Client, pod *corev1.Pod, minReadyPercent int32) error {
+	pods := []corev1.Pod{*pod}
+
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		list := &corev1.PodList{}
+		if err := c.List(ctx, list, client.InNamespace(pod.Namespace)); err != nil {
+			return err
+		}
+
+		pods = make([]corev1.Pod, 0, len(list.Items))
+		for _, p := range list.Items {
+			if ref := metav1.GetControllerOf(&p); ref != nil && ref.UID == owner.UID {
+				pods = append(pods, p)
+			}
+		}
+	}
+
+	ready := int32(0)
+	for _, p := range pods {
+		if p.Name != pod.Name && isPodReady(&p) {
+			ready++
+		}
+	}
+
+	total := int32(len(pods))
+	if !hasMinReady(ready, total, minReadyPercent) {
+		return fmt.Errorf("deleting pod '%s/%s' would leave %d of %d pods ready, below the minimum of %d%%",
+			pod.Namespace, pod.Name, ready, total, minReadyPercent)
+	}
+
+	return nil
+}
+
+func hasMinReady(ready, total, minReadyPercent int32) bool {
+	if total == 0 {
+		return true
+	}
+	return ready*100 >= minReadyPercent*total
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
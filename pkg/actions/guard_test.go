@@ -0,0 +1,181 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDelete_CheckGuard(t *testing.T) {
+	controller := true
+	owner := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs", UID: "rs-uid", Controller: &controller}
+
+	objs := make([]runtime.Object, 0)
+	for i, ready := range []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionTrue, corev1.ConditionFalse} {
+		pod := newWaitForPod(fmt.Sprintf("pod-%d", i), ready)
+		pod.OwnerReferences = []metav1.OwnerReference{owner}
+		objs = append(objs, pod)
+	}
+
+	protected := newWaitForPod("protected", corev1.ConditionTrue)
+	protected.Labels[v1alpha1.ProtectedLabel] = "true"
+	objs = append(objs, protected)
+
+	k8sClient := fake.NewClientBuilder().WithRuntimeObjects(objs...).Build()
+
+	tests := []struct {
+		name            string
+		pod             string
+		minReadyPercent *int32
+		wantErr         string
+	}{
+		{
+			name:    "protected",
+			pod:     "protected",
+			wantErr: "'test-namespace/protected' is protected with the label countermeasures/protected=true",
+		},
+		{
+			name:            "not ready pod",
+			pod:             "pod-2",
+			minReadyPercent: int32Ptr(60),
+		},
+		{
+			name:            "ready pod",
+			pod:             "pod-0",
+			minReadyPercent: int32Ptr(60),
+			wantErr:         "deleting pod 'test-namespace/pod-0' would leave 1 of 3 pods ready, below the minimum of 60%",
+		},
+		{
+			name: "no guard",
+			pod:  "pod-0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := NewDeleteAction(k8sClient, v1alpha1.DeleteSpec{
+				TargetObjectRef: v1alpha1.ObjectReference{
+					Namespace:  PodNamespace,
+					Name:       tt.pod,
+					Kind:       "Pod",
+					ApiVersion: "v1",
+				},
+			})
+			action.Guard = &v1alpha1.GuardSpec{MinReadyPercent: tt.minReadyPercent}
+
+			err := action.CheckGuard(context.TODO(), events.Event{})
+			if len(tt.wantErr) > 0 {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRestart_CheckGuard(t *testing.T) {
+	replicas := int32(4)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DeploymentName,
+			Namespace: DeploymentNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.DeploymentStatus{
+			ReadyReplicas: 2,
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithRuntimeObjects(deployment).Build()
+	action := NewRestartAction(k8sClient, v1alpha1.RestartSpec{
+		DeploymentRef: v1alpha1.DeploymentReference{
+			Namespace: DeploymentNamespace,
+			Name:      DeploymentName,
+		},
+	})
+
+	assert.NoError(t, action.CheckGuard(context.TODO(), events.Event{}))
+
+	action.Guard = &v1alpha1.GuardSpec{MinReadyPercent: int32Ptr(50)}
+	assert.NoError(t, action.CheckGuard(context.TODO(), events.Event{}))
+
+	action.Guard = &v1alpha1.GuardSpec{MinReadyPercent: int32Ptr(75)}
+	assert.Error(t, action.CheckGuard(context.TODO(), events.Event{}))
+}
+
+func TestPatch_CheckGuard(t *testing.T) {
+	replicas := int32(4)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DeploymentName,
+			Namespace: DeploymentNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.DeploymentStatus{
+			ReadyReplicas: 2,
+		},
+	}
+
+	protected := deployment.DeepCopy()
+	protected.Name = "protected"
+	protected.Labels = map[string]string{v1alpha1.ProtectedLabel: "true"}
+
+	k8sClient := fake.NewClientBuilder().WithRuntimeObjects(deployment, protected).Build()
+	newPatch := func(name string) *Patch {
+		return NewPatchAction(k8sClient, v1alpha1.PatchSpec{
+			TargetObjectRef: v1alpha1.ObjectReference{
+				Namespace:  DeploymentNamespace,
+				Name:       name,
+				Kind:       "Deployment",
+				ApiVersion: "apps/v1",
+			},
+		})
+	}
+
+	action := newPatch("protected")
+	assert.EqualError(t, action.CheckGuard(context.TODO(), events.Event{}),
+		fmt.Sprintf("'%s/protected' is protected with the label countermeasures/protected=true", DeploymentNamespace))
+
+	action = newPatch(DeploymentName)
+	assert.NoError(t, action.CheckGuard(context.TODO(), events.Event{}))
+
+	action.Guard = &v1alpha1.GuardSpec{MinReadyPercent: int32Ptr(50)}
+	assert.NoError(t, action.CheckGuard(context.TODO(), events.Event{}))
+
+	action.Guard = &v1alpha1.GuardSpec{MinReadyPercent: int32Ptr(75)}
+	assert.EqualError(t, action.CheckGuard(context.TODO(), events.Event{}),
+		fmt.Sprintf("deployment '%s/%s' has 2 of 4 pods ready, below the minimum of 75%%", DeploymentNamespace, DeploymentName))
+}
+
+func TestDebug_CheckGuard(t *testing.T) {
+	protected := newWaitForPod("protected", corev1.ConditionTrue)
+	protected.Labels[v1alpha1.ProtectedLabel] = "true"
+
+	k8sClient := fake.NewClientBuilder().WithRuntimeObjects(protected, newWaitForPod("pod", corev1.ConditionTrue)).Build()
+	newDebug := func(name string) *Debug {
+		return NewDebugAction(nil, k8sClient, v1alpha1.DebugSpec{
+			PodRef: v1alpha1.PodReference{Namespace: PodNamespace, Name: name},
+		})
+	}
+
+	assert.EqualError(t, newDebug("protected").CheckGuard(context.TODO(), events.Event{}),
+		"'test-namespace/protected' is protected with the label countermeasures/protected=true")
+	assert.NoError(t, newDebug("pod").CheckGuard(context.TODO(), events.Event{}))
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
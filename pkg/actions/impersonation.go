@@ -0,0 +1,53 @@
+package actions
+
+import (
+	"fmt"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// serviceAccountUserName the user name a ServiceAccount is authenticated as by the API server.
+func serviceAccountUserName(namespace, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
+
+// actionClient returns the client and rest config the actions of the countermeasure are taken with,
+// impersonating the ServiceAccount of the countermeasure when it has one.
+func (m *Manager) actionClient(cm *v1alpha1.CounterMeasure) (client.Client, *rest.Config, error) {
+	if cm.Spec.ServiceAccountName == "" {
+		return m.client, m.restConfig, nil
+	}
+
+	if m.restConfig == nil {
+		return nil, nil, fmt.Errorf("unable to impersonate service account '%s', no rest config", cm.Spec.ServiceAccountName)
+	}
+
+	config := rest.CopyConfig(m.restConfig)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: serviceAccountUserName(cm.Namespace, cm.Spec.ServiceAccountName),
+	}
+
+	m.impersonatedMux.Lock()
+	defer m.impersonatedMux.Unlock()
+
+	if c, ok := m.impersonated[config.Impersonate.UserName]; ok {
+		return c, config, nil
+	}
+
+	c, err := client.New(config, client.Options{
+		Scheme: m.client.Scheme(),
+		Mapper: m.client.RESTMapper(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to impersonate service account '%s': %w", cm.Spec.ServiceAccountName, err)
+	}
+
+	if m.impersonated == nil {
+		m.impersonated = make(map[string]client.Client)
+	}
+	m.impersonated[config.Impersonate.UserName] = c
+
+	return c, config, nil
+}
@@ -0,0 +1,62 @@
+package actions
+
+import (
+	"testing"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+)
+
+func TestManager_ActionClient(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("impersonated"),
+	}
+
+	k8sClient := newRunsClient()
+	restConfig := &rest.Config{Host: "https://localhost:6443"}
+	mgr := &Manager{client: k8sClient, restConfig: restConfig}
+
+	// without a service account the actions are taken as the operator
+	c, config, err := mgr.actionClient(cm)
+	assert.NoError(t, err)
+	assert.Equal(t, k8sClient, c)
+	assert.Equal(t, restConfig, config)
+
+	cm.Spec.ServiceAccountName = "remediator"
+	c, config, err = mgr.actionClient(cm)
+	assert.NoError(t, err)
+	assert.NotEqual(t, k8sClient, c)
+	assert.Equal(t, "system:serviceaccount:ns:remediator", config.Impersonate.UserName)
+	assert.Empty(t, restConfig.Impersonate.UserName, "operator config shouldn't be modified")
+
+	// the impersonating client is reused
+	again, _, err := mgr.actionClient(cm)
+	assert.NoError(t, err)
+	assert.Same(t, c, again)
+
+	_, _, err = (&Manager{client: k8sClient}).actionClient(cm)
+	assert.Error(t, err)
+}
+
+func TestRegistry_ImpersonatedActions(t *testing.T) {
+	registry := Registry{}
+	registry.Initialize()
+
+	operator := newRunsClient()
+	impersonated := newRunsClient()
+
+	action, err := registry.create(ActionContext{Client: operator, ActionClient: impersonated}, v1alpha1.Action{
+		Name:   "delete",
+		Delete: &v1alpha1.DeleteSpec{},
+	}, false)
+	assert.NoError(t, err)
+	assert.Same(t, impersonated, action.(*Delete).client)
+
+	action, err = registry.create(ActionContext{Client: operator}, v1alpha1.Action{
+		Name:   "delete",
+		Delete: &v1alpha1.DeleteSpec{},
+	}, false)
+	assert.NoError(t, err)
+	assert.Same(t, operator, action.(*Delete).client)
+}
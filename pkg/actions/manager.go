@@ -2,14 +2,18 @@ package actions
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
 	sourceV1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
 
 	"github.com/dvilaverde/k8s-countermeasures/pkg/actions/state"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -30,11 +34,30 @@ type Manager struct {
 	recorder   record.EventRecorder
 
 	consumersMux sync.RWMutex
-	consumers    map[types.NamespacedName]eventbus.Consumer
+	// the consumers of the event and the trigger topics of each countermeasure
+	consumers map[types.NamespacedName][]eventbus.Consumer
 
 	state          *state.ActionState
 	eventbus       *eventbus.EventBus
 	ActionRegistry Registry
+	rateLimiter    *RateLimiter
+	policies       client.Reader
+
+	impersonatedMux sync.Mutex
+	// clients impersonating ServiceAccounts keyed by user name
+	impersonated map[string]client.Client
+
+	// Verifier is used to check if the triggering event is still active after the
+	// actions of a countermeasure with a verify spec complete.
+	Verifier producer.EventVerifier
+
+	// AllowCrossNamespaceTargets lets countermeasures target objects outside their namespace
+	// without a CounterMeasureNamespaceGrant.
+	AllowCrossNamespaceTargets bool
+
+	// ClusterRunNamespace is the namespace the CounterMeasureRuns of the cluster countermeasures
+	// are created in.
+	ClusterRunNamespace string
 }
 
 // NewFromManager construct a new action manager
@@ -48,8 +71,10 @@ func NewFromManager(mgr controller.Manager, bus *eventbus.EventBus) *Manager {
 		restConfig:     mgr.GetConfig(),
 		recorder:       mgr.GetEventRecorderFor("action_manager"),
 		ActionRegistry: actionRegistry,
+		rateLimiter:    NewRateLimiter(mgr.GetClient()),
+		policies:       mgr.GetClient(),
 		consumersMux:   sync.RWMutex{},
-		consumers:      make(map[types.NamespacedName]eventbus.Consumer),
+		consumers:      make(map[types.NamespacedName][]eventbus.Consumer),
 		state:          state.NewState(),
 	}
 }
@@ -92,46 +117,216 @@ func (m *Manager) Add(cm *v1alpha1.CounterMeasure) error {
 		return err
 	}
 
+	key := manager.ToKey(cm.ObjectMeta)
+
+	// the events of the triggers naming this countermeasure are only delivered to it
+	triggers, err := m.eventbus.Subscribe(TriggerTopic(key.NamespacedName))
+	if err != nil {
+		consumer.UnSubscribe()
+		return err
+	}
+
 	m.consumersMux.Lock()
 	defer m.consumersMux.Unlock()
 
-	key := manager.ToKey(cm.ObjectMeta)
-	m.consumers[key.NamespacedName] = consumer
+	m.consumers[key.NamespacedName] = []eventbus.Consumer{consumer, triggers}
 	m.state.Add(cm.DeepCopy())
 
-	go func(c eventbus.Consumer, key manager.ObjectKey) {
-		for evt := range c.OnEvent() {
-			if entry := m.state.GetCounterMeasure(key); entry != nil {
-				// if this action is already running then prevent it from running again.
-				if entry.IsSuppressed(evt) {
-					m.recorder.Event(entry.Countermeasure, "Normal", "Skipping", "Previous execution is still in progress or suppressed.")
-					continue
-				}
-
-				actionContext := ActionContext{
-					Client:         m.client,
-					RestConfig:     m.restConfig,
-					Recorder:       m.recorder,
-					CounterMeasure: *entry.Countermeasure,
-				}
-
-				actionRunner, err := m.ActionRegistry.NewRunner(actionContext)
-				if err != nil {
-					utilruntime.HandleError(err)
-					continue
-				}
-
-				m.state.CounterMeasureStart(evt, key)
-				actionRunner.Run(actionContext, evt)
-				m.state.CounterMeasureEnd(evt, key)
+	go func(onEvent, onTrigger <-chan events.Event, key manager.ObjectKey) {
+		for {
+			var evt events.Event
+			var ok bool
+			select {
+			case evt, ok = <-onEvent:
+			case evt, ok = <-onTrigger:
+			}
+
+			// both channels are closed when the countermeasure is removed
+			if !ok {
+				return
 			}
 
+			m.onEvent(key, evt)
 		}
-	}(consumer, key)
+	}(consumer.OnEvent(), triggers.OnEvent(), key)
 
 	return nil
 }
 
+// onEvent runs the countermeasure for an event unless it's suppressed, the circuit breaker is
+// tripped, it's outside the schedule or it needs to be approved first.
+func (m *Manager) onEvent(key manager.ObjectKey, evt events.Event) {
+	entry := m.state.GetCounterMeasure(key)
+	if entry == nil {
+		return
+	}
+
+	// the events scoped to a namespace are only for the countermeasures in it
+	if len(evt.Namespace) > 0 && evt.Namespace != entry.Countermeasure.Namespace {
+		return
+	}
+
+	// if this action is already running then prevent it from running again.
+	if entry.IsSuppressed(evt, m.targets(entry.Countermeasure, evt)...) {
+		m.recorder.Event(entry.Countermeasure, "Normal", "Skipping", "Previous execution is still in progress or suppressed.")
+		return
+	}
+
+	if entry.Countermeasure.Spec.CircuitBreaker != nil && m.isTripped(context.Background(), key.NamespacedName) {
+		m.recorder.Event(entry.Countermeasure, "Normal", "Skipping", "Circuit breaker is tripped.")
+		return
+	}
+
+	cm, ok := m.applySchedule(entry.Countermeasure, evt)
+	if !ok {
+		return
+	}
+
+	if cm.Spec.Approval != nil {
+		if err := m.requestApproval(cm, evt); err != nil {
+			utilruntime.HandleError(err)
+			m.recorder.Event(cm, "Warning", "ApprovalError", err.Error())
+		}
+		return
+	}
+
+	m.execute(key, cm, evt, nil)
+}
+
+// execute runs the actions of the countermeasure for an event, recording the execution in
+// the CounterMeasureRun, which is created when run is nil.
+func (m *Manager) execute(key manager.ObjectKey, cm *v1alpha1.CounterMeasure, evt events.Event, run *types.NamespacedName) {
+	ctx := context.Background()
+	actionClient, restConfig, err := m.actionClient(cm)
+	if err != nil {
+		utilruntime.HandleError(err)
+		m.recorder.Event(cm, "Warning", "ImpersonationError", err.Error())
+		if run != nil {
+			m.completeRun(ctx, cm, *run, err)
+		}
+		return
+	}
+
+	restriction, err := m.namespaceRestriction(ctx, cm)
+	if err != nil {
+		utilruntime.HandleError(err)
+		if run != nil {
+			m.completeRun(ctx, cm, *run, err)
+		}
+		return
+	}
+
+	actionContext := ActionContext{
+		Client:         m.client,
+		ActionClient:   actionClient,
+		RestConfig:     restConfig,
+		Recorder:       m.recorder,
+		CounterMeasure: *cm,
+		RateLimiter:    m.rateLimiter,
+		Policies:       m.policies,
+		Restriction:    restriction,
+	}
+
+	actionRunner, err := m.ActionRegistry.NewRunner(actionContext)
+	if err != nil {
+		utilruntime.HandleError(err)
+		if run != nil {
+			m.completeRun(ctx, cm, *run, err)
+		}
+		return
+	}
+
+	if run == nil {
+		status := v1alpha1.CounterMeasureRunStatus{
+			Phase:     v1alpha1.Running,
+			StartTime: &metav1.Time{Time: time.Now()},
+		}
+
+		// failing to record the run shouldn't prevent the countermeasure from running
+		created, err := m.createRun(ctx, cm, evt, actionRunner, status, nil)
+		if err != nil {
+			managerLog.Error(err, "failed to create countermeasure run", "name", cm.Name, "namespace", cm.Namespace)
+		} else {
+			runKey := client.ObjectKeyFromObject(created)
+			run = &runKey
+		}
+	}
+	actionContext.Run = run
+
+	targets := m.targets(cm, evt)
+	m.state.CounterMeasureStart(evt, key, targets...)
+	if entry := m.state.GetCounterMeasure(key); entry != nil {
+		for _, suppressionKey := range entry.SuppressionKeys(evt, targets...) {
+			until, ok := entry.SuppressedUntil(suppressionKey)
+			if !ok {
+				continue
+			}
+
+			if err := addSuppression(ctx, m.client, key.NamespacedName, suppressionKey, until); err != nil {
+				managerLog.Error(err, "failed to persist suppression", "name", cm.Name, "namespace", cm.Namespace)
+			}
+		}
+	}
+
+	err = actionRunner.Run(actionContext, evt)
+	if run != nil {
+		m.completeRun(ctx, cm, *run, err)
+	}
+
+	// refused actions didn't fail, only failures count against the circuit breaker
+	if err != nil && !IsRefused(err) {
+		m.recordFailure(ctx, cm)
+	}
+
+	if err == nil && m.shouldVerify(cm) {
+		if m.canVerify(evt) {
+			// the countermeasure stays running until the verification completes so
+			// the same event isn't acted on again during the grace period.
+			go func() {
+				m.verify(cm, evt)
+				m.state.CounterMeasureEnd(evt, key)
+			}()
+			return
+		}
+
+		m.recorder.Event(cm, "Warning", "VerificationSkipped",
+			fmt.Sprintf("Event '%s' can't be verified, its event source doesn't support verification", evt.Name))
+	}
+
+	if err == nil {
+		m.recordSuccess(ctx, cm)
+	}
+	m.state.CounterMeasureEnd(evt, key)
+}
+
+// targets returns the rendered targets of the actions when the countermeasure suppresses
+// duplicates by target.
+func (m *Manager) targets(cm *v1alpha1.CounterMeasure, evt events.Event) []string {
+	policy := cm.Spec.OnEvent.SuppressionPolicy
+	if policy == nil || policy.Mode != v1alpha1.SuppressByTarget {
+		return nil
+	}
+
+	actionRunner, err := m.ActionRegistry.NewRunner(ActionContext{
+		Client:         m.client,
+		RestConfig:     m.restConfig,
+		Recorder:       m.recorder,
+		CounterMeasure: *cm,
+	})
+	if err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	planned := actionRunner.Plan(evt)
+	targets := make([]string, 0, len(planned))
+	for _, action := range planned {
+		targets = append(targets, action.Target)
+	}
+
+	return targets
+}
+
 // Remove uninstall a countermeasure from the event subscription
 func (m *Manager) Remove(name types.NamespacedName) error {
 	m.consumersMux.Lock()
@@ -139,7 +334,7 @@ func (m *Manager) Remove(name types.NamespacedName) error {
 
 	// make sure to unsubscribe to stop the running goroutine, otherwise we'll
 	// have a goroutine leak
-	if consumer, ok := m.consumers[name]; ok {
+	for _, consumer := range m.consumers[name] {
 		consumer.UnSubscribe()
 	}
 
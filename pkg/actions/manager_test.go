@@ -75,6 +75,55 @@ func TestManager_OnEvent(t *testing.T) {
 	}, time.Second*500, time.Millisecond*500, "expected there to be a suppress event")
 }
 
+func TestManager_OnEventOtherNamespace(t *testing.T) {
+	eventsMux := sync.Mutex{}
+	recordedEvents := make([]string, 0)
+
+	bus := eventbus.NewEventBus(1)
+	bus.InjectLogger(testr.New(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Start(ctx)
+
+	mgr, eventsCh := Deploy(t, bus)
+
+	go func() {
+		for s := range eventsCh {
+			eventsMux.Lock()
+			recordedEvents = append(recordedEvents, s)
+			eventsMux.Unlock()
+		}
+	}()
+
+	mgr.ActionRegistry.RegisterAction(v1alpha1.RestartSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
+		return &MockAction{}
+	})
+
+	e := events.Event{
+		Name:       "event2",
+		ActiveTime: time.Now(),
+		Data: &events.EventData{
+			"prop1": "value1",
+		},
+		Namespace: "other",
+	}
+
+	bus.Publish("event2", e)
+	assert.Never(t, func() bool {
+		eventsMux.Lock()
+		defer eventsMux.Unlock()
+		return len(recordedEvents) > 0
+	}, time.Second, time.Millisecond*100, "expected the event of another namespace to be dropped")
+
+	e.Namespace = "ns"
+	bus.Publish("event2", e)
+	assert.Eventually(t, func() bool {
+		eventsMux.Lock()
+		defer eventsMux.Unlock()
+		return len(recordedEvents) == 1 && !strings.Contains(recordedEvents[0], "Skipping")
+	}, time.Second*5, time.Millisecond*100, "expected the action to run")
+}
+
 func TestManager_Add(t *testing.T) {
 	bus := eventbus.NewEventBus(1)
 	bus.InjectLogger(testr.New(t))
@@ -149,7 +198,7 @@ func Deploy(t *testing.T, bus *eventbus.EventBus) (*Manager, <-chan string) {
 		recorder:       recorder,
 		ActionRegistry: actionRegistry,
 		eventbus:       bus,
-		consumers:      make(map[types.NamespacedName]eventbus.Consumer),
+		consumers:      make(map[types.NamespacedName][]eventbus.Consumer),
 		state:          state.NewState(),
 	}
 	managerLog = testr.New(t)
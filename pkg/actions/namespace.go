@@ -0,0 +1,121 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NamespaceRestriction limits the targets of the actions to the namespace of the countermeasure,
+// unless a CounterMeasureNamespaceGrant in the target namespace grants access. The targets of
+// cluster countermeasures are limited to the namespaces matching their selector instead, or to
+// cluster scoped objects when they have no selector and cross namespace targets aren't allowed.
+type NamespaceRestriction struct {
+	// Namespace of the countermeasure
+	Namespace string
+	// Selector of the namespaces the targets of a cluster countermeasure may be in
+	Selector labels.Selector
+	// Reader reads the CounterMeasureNamespaceGrants and Namespaces
+	Reader client.Reader
+}
+
+// Allows checks if the actions may target objects in the namespace, a nil restriction
+// allows every namespace. A namespace that isn't allowed is refused with a RefusedError.
+func (r *NamespaceRestriction) Allows(ctx context.Context, namespace string) error {
+	if r == nil {
+		return nil
+	}
+
+	if r.Selector != nil {
+		return r.selects(ctx, namespace)
+	}
+
+	if namespace == r.Namespace {
+		return nil
+	}
+
+	if namespace == "" {
+		return namespaceDenied(fmt.Errorf("countermeasures in namespace '%s' can't target cluster scoped objects or all namespaces", r.Namespace))
+	}
+
+	grants := &v1alpha1.CounterMeasureNamespaceGrantList{}
+	if err := r.Reader.List(ctx, grants, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+
+	for _, grant := range grants.Items {
+		if grant.Grants(r.Namespace) {
+			return nil
+		}
+	}
+
+	return namespaceDenied(fmt.Errorf("namespace '%s' has no CounterMeasureNamespaceGrant for countermeasures in namespace '%s'",
+		namespace, r.Namespace))
+}
+
+// selects checks the namespace matches the selector, cluster scoped targets aren't in any namespace
+// so they're always allowed.
+func (r *NamespaceRestriction) selects(ctx context.Context, namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+
+	if _, selectable := r.Selector.Requirements(); !selectable {
+		return namespaceDenied(fmt.Errorf("cluster countermeasures without a namespace selector can't target namespace '%s'", namespace))
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Reader.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return err
+	}
+
+	if !r.Selector.Matches(labels.Set(ns.Labels)) {
+		return namespaceDenied(fmt.Errorf("namespace '%s' is not selected by the namespace selector '%s'", namespace, r.Selector))
+	}
+
+	return nil
+}
+
+// namespaceDenied refuses a target outside of the allowed namespaces
+func namespaceDenied(err error) error {
+	return &RefusedError{Reason: "NamespaceDenied", Err: err}
+}
+
+// namespaceRestriction the restriction of the targets of the countermeasure, nil when
+// the targets may be in any namespace.
+func (m *Manager) namespaceRestriction(ctx context.Context, cm *v1alpha1.CounterMeasure) (*NamespaceRestriction, error) {
+	if isClusterScoped(cm) {
+		ccm := &v1alpha1.ClusterCounterMeasure{}
+		if err := m.client.Get(ctx, client.ObjectKeyFromObject(cm), ccm); err != nil {
+			return nil, err
+		}
+
+		if ccm.Spec.NamespaceSelector == nil {
+			if m.AllowCrossNamespaceTargets {
+				return nil, nil
+			}
+			return &NamespaceRestriction{Selector: labels.Nothing(), Reader: m.client}, nil
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(ccm.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		return &NamespaceRestriction{Selector: selector, Reader: m.client}, nil
+	}
+
+	if m.AllowCrossNamespaceTargets {
+		return nil, nil
+	}
+
+	return &NamespaceRestriction{
+		Namespace: cm.Namespace,
+		Reader:    m.client,
+	}, nil
+}
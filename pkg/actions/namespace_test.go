@@ -0,0 +1,122 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestNamespaceRestriction_Allows(t *testing.T) {
+	grants := newRunsClient(
+		&v1alpha1.CounterMeasureNamespaceGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "shared"},
+			Spec:       v1alpha1.CounterMeasureNamespaceGrantSpec{FromNamespaces: []string{"team-a"}},
+		},
+		&v1alpha1.CounterMeasureNamespaceGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "everyone", Namespace: "public"},
+			Spec:       v1alpha1.CounterMeasureNamespaceGrantSpec{FromNamespaces: []string{"*"}},
+		},
+	)
+
+	restriction := &NamespaceRestriction{Namespace: "team-a", Reader: grants}
+	ctx := context.TODO()
+
+	assert.NoError(t, restriction.Allows(ctx, "team-a"))
+	assert.NoError(t, restriction.Allows(ctx, "shared"))
+	assert.NoError(t, restriction.Allows(ctx, "public"))
+	assert.Error(t, restriction.Allows(ctx, "team-b"))
+	assert.Error(t, restriction.Allows(ctx, ""))
+	assert.True(t, IsRefused(restriction.Allows(ctx, "team-b")))
+
+	restriction.Namespace = "team-b"
+	assert.Error(t, restriction.Allows(ctx, "shared"))
+
+	var unrestricted *NamespaceRestriction
+	assert.NoError(t, unrestricted.Allows(ctx, "team-b"))
+}
+
+func TestDelete_PerformRestricted(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PodName,
+			Namespace: PodNamespace,
+			Labels:    map[string]string{"app": "test-app"},
+		},
+	}
+	k8sClient := newRunsClient(pod)
+
+	spec := v1alpha1.Action{
+		Name: "delete",
+		Delete: &v1alpha1.DeleteSpec{
+			TargetObjectRef: v1alpha1.ObjectReference{
+				Namespace:  "{{ .Data.namespace }}",
+				Name:       PodName,
+				Kind:       "Pod",
+				ApiVersion: "v1",
+			},
+		},
+	}
+
+	registry := Registry{}
+	registry.Initialize()
+	action, err := registry.create(ActionContext{
+		Client:      k8sClient,
+		Restriction: &NamespaceRestriction{Namespace: "team-a", Reader: k8sClient},
+	}, spec, false)
+	assert.NoError(t, err)
+
+	// the rendered namespace isn't the namespace of the countermeasure
+	data := events.EventData{"namespace": PodNamespace}
+	err = action.Perform(context.TODO(), events.Event{Data: &data})
+	assert.ErrorContains(t, err, "has no CounterMeasureNamespaceGrant")
+	assertPodExists(t, k8sClient, 1)
+}
+
+func TestInMemoryRunner_RestrictedBeforeGuard(t *testing.T) {
+	cm := v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("restricted"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			Actions: []v1alpha1.Action{
+				{
+					Name: "delete",
+					Delete: &v1alpha1.DeleteSpec{
+						TargetObjectRef: v1alpha1.ObjectReference{Namespace: "team-b", Name: PodName, Kind: "Pod", ApiVersion: "v1"},
+					},
+				},
+			},
+		},
+	}
+
+	k8sClient := newRunsClient()
+	recorder := record.NewFakeRecorder(10)
+	guarded := &guardedAction{}
+	runner := InMemoryRunner{guarded}
+
+	err := runner.Run(ActionContext{
+		Client:         k8sClient,
+		Recorder:       recorder,
+		CounterMeasure: cm,
+		Restriction:    &NamespaceRestriction{Namespace: cm.Namespace, Reader: k8sClient},
+	}, events.Event{})
+
+	assert.True(t, IsRefused(err))
+	assert.Contains(t, <-recorder.Events, "NamespaceDenied")
+	assert.False(t, guarded.checked)
+}
+
+// guardedAction records if its guard was checked
+type guardedAction struct {
+	MockAction
+	checked bool
+}
+
+func (g *guardedAction) CheckGuard(context.Context, events.Event) error {
+	g.checked = true
+	return nil
+}
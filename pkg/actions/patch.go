@@ -3,10 +3,12 @@ package actions
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"text/template"
 
 	"github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	jsonpatch "github.com/evanphx/json-patch"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
@@ -20,8 +22,13 @@ type PatchData struct {
 type Patch struct {
 	BaseAction
 	spec v1alpha1.PatchSpec
+
+	// revert is the merge patch that restores the object, created by Perform
+	revert *v1alpha1.InferredPatch
 }
 
+var _ Reversible = &Patch{}
+
 func NewPatchAction(client client.Client, spec v1alpha1.PatchSpec) *Patch {
 	return NewPatchFromBase(BaseAction{
 		client: client,
@@ -56,6 +63,10 @@ func (p *Patch) Perform(ctx context.Context, event events.Event) error {
 	object.SetGroupVersionKind(gvk)
 
 	target := p.spec.TargetObjectRef
+	if err := p.checkNamespace(ctx, target.Namespace, event); err != nil {
+		return err
+	}
+
 	objectName := ObjectKeyFromTemplate(target.Namespace, target.Name, event)
 
 	if err = p.client.Get(ctx, objectName, object); err != nil {
@@ -76,8 +87,56 @@ func (p *Patch) Perform(ctx context.Context, event events.Event) error {
 		opts = append(opts, client.DryRunAll)
 	}
 
-	err = p.client.Patch(ctx, object, patch, opts...)
-	return err
+	original := object.DeepCopy()
+	if err = p.client.Patch(ctx, object, patch, opts...); err != nil {
+		return err
+	}
+
+	revertPatch, err := createRevertPatch(original, object)
+	if err != nil {
+		return err
+	}
+
+	renderedTarget := target
+	renderedTarget.Namespace = objectName.Namespace
+	renderedTarget.Name = objectName.Name
+	p.revert = &v1alpha1.InferredPatch{
+		TargetObjectRef: renderedTarget,
+		Patch:           string(revertPatch),
+	}
+
+	return nil
+}
+
+// InferUndo returns the merge patch that restores the object to the state before
+// it was patched, only available after the patch was performed.
+func (p *Patch) InferUndo() *v1alpha1.InferredPatch {
+	return p.revert
+}
+
+// createRevertPatch creates a merge patch that reverts the modified object back to the original
+func createRevertPatch(original, modified *unstructured.Unstructured) ([]byte, error) {
+	originalJSON, err := json.Marshal(withoutServerFields(original).Object)
+	if err != nil {
+		return nil, err
+	}
+
+	modifiedJSON, err := json.Marshal(withoutServerFields(modified).Object)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonpatch.CreateMergePatch(modifiedJSON, originalJSON)
+}
+
+// withoutServerFields removes the fields managed by the API server which can't be reverted.
+func withoutServerFields(object *unstructured.Unstructured) *unstructured.Unstructured {
+	stripped := object.DeepCopy()
+	unstructured.RemoveNestedField(stripped.Object, "status")
+	unstructured.RemoveNestedField(stripped.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(stripped.Object, "metadata", "generation")
+	unstructured.RemoveNestedField(stripped.Object, "metadata", "managedFields")
+	return stripped
 }
 
 func (p *Patch) createPatch(data PatchData) (client.Patch, error) {
@@ -0,0 +1,42 @@
+package actions
+
+import (
+	"context"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// checkTargetPolicies checks the target of the action, rendered from the event, is allowed by
+// every CounterMeasurePolicy. The templates are only rendered when the action is taken, so
+// unlike the webhook validation this also catches templated namespaces.
+func checkTargetPolicies(ctx context.Context, c client.Reader, spec v1alpha1.Action, event events.Event) error {
+	target := spec.TargetRef()
+	if target == nil {
+		return nil
+	}
+
+	gvk, err := target.ToGroupVersionKind()
+	if err != nil {
+		return err
+	}
+
+	policies := &v1alpha1.CounterMeasurePolicyList{}
+	if err := c.List(ctx, policies); err != nil {
+		return err
+	}
+
+	namespace := evaluateTemplate(target.Namespace, event)
+	for _, policy := range policies.Items {
+		if policy.Spec.Targets == nil {
+			continue
+		}
+
+		if err := policy.Spec.Targets.Allows(namespace, gvk.GroupKind()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
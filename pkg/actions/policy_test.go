@@ -0,0 +1,89 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckTargetPolicies(t *testing.T) {
+	policy := &v1alpha1.CounterMeasurePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: v1alpha1.CounterMeasurePolicySpec{
+			Targets: &v1alpha1.TargetPolicy{
+				DeniedNamespaces: []string{"kube-system"},
+				DeniedKinds:      []v1alpha1.KindReference{{Group: "rbac.authorization.k8s.io", Kind: "*"}},
+				AllowedKinds: []v1alpha1.KindReference{
+					{Group: "", Kind: "Pod"},
+					{Group: "apps", Kind: "Deployment"},
+				},
+			},
+		},
+	}
+
+	k8sClient := newRunsClient(policy)
+
+	deletePod := v1alpha1.Action{
+		Name: "delete",
+		Delete: &v1alpha1.DeleteSpec{
+			TargetObjectRef: v1alpha1.ObjectReference{
+				Namespace:  "{{ .Data.namespace }}",
+				Name:       "{{ .Data.pod }}",
+				Kind:       "Pod",
+				ApiVersion: "v1",
+			},
+		},
+	}
+
+	deleteRole := v1alpha1.Action{
+		Name: "delete",
+		Delete: &v1alpha1.DeleteSpec{
+			TargetObjectRef: v1alpha1.ObjectReference{
+				Namespace:  "{{ .Data.namespace }}",
+				Name:       "role",
+				Kind:       "Role",
+				ApiVersion: "rbac.authorization.k8s.io/v1",
+			},
+		},
+	}
+
+	restart := v1alpha1.Action{
+		Name: "restart",
+		Restart: &v1alpha1.RestartSpec{
+			DeploymentRef: v1alpha1.DeploymentReference{Namespace: "{{ .Data.namespace }}", Name: "app"},
+		},
+	}
+
+	ctx := context.TODO()
+	appEvent := events.Event{Data: &events.EventData{"namespace": "app", "pod": "pod"}}
+	systemEvent := events.Event{Data: &events.EventData{"namespace": "kube-system", "pod": "pod"}}
+
+	assert.NoError(t, checkTargetPolicies(ctx, k8sClient, deletePod, appEvent))
+	assert.NoError(t, checkTargetPolicies(ctx, k8sClient, restart, appEvent))
+	assert.EqualError(t, checkTargetPolicies(ctx, k8sClient, deletePod, systemEvent),
+		"namespace 'kube-system' is not allowed by the countermeasure policy")
+	assert.EqualError(t, checkTargetPolicies(ctx, k8sClient, deleteRole, appEvent),
+		"kind 'Role.rbac.authorization.k8s.io' is not allowed by the countermeasure policy")
+
+	// the webhook validation skips the templated namespaces
+	assert.NoError(t, v1alpha1.ValidateTargetPolicies(ctx, k8sClient, []v1alpha1.Action{deletePod}))
+	deleteRole.Delete.TargetObjectRef.Namespace = "app"
+	assert.Error(t, v1alpha1.ValidateTargetPolicies(ctx, k8sClient, []v1alpha1.Action{deleteRole}))
+
+	// the targets of the undo actions are validated too
+	labelPod := v1alpha1.Action{
+		Name: "label",
+		Patch: &v1alpha1.PatchSpec{
+			TargetObjectRef: v1alpha1.ObjectReference{Namespace: "app", Name: "pod", Kind: "Pod", ApiVersion: "v1"},
+		},
+		Undo: &v1alpha1.UndoSpec{OnResolve: true},
+	}
+	assert.NoError(t, v1alpha1.ValidateTargetPolicies(ctx, k8sClient, []v1alpha1.Action{labelPod}))
+
+	labelPod.Undo.Delete = deleteRole.Delete
+	assert.ErrorContains(t, v1alpha1.ValidateTargetPolicies(ctx, k8sClient, []v1alpha1.Action{labelPod}), "action 'label-undo'")
+}
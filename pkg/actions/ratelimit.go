@@ -0,0 +1,109 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RateLimiter enforces the rate limits of the CounterMeasurePolicies across all countermeasures,
+// counting the actions taken in a sliding window both cluster wide and per namespace.
+type RateLimiter struct {
+	client client.Reader
+
+	mux sync.Mutex
+	// times the actions were taken keyed by action type (cluster wide) or namespace/type
+	taken map[string][]time.Time
+}
+
+// NewRateLimiter creates a rate limiter reading the policies with the client.
+func NewRateLimiter(c client.Reader) *RateLimiter {
+	return &RateLimiter{
+		client: c,
+		taken:  make(map[string][]time.Time),
+	}
+}
+
+// Allow checks if an action of the type can be taken in the namespace, and when allowed
+// counts the action against the limits. An error is returned when a limit is exhausted.
+func (r *RateLimiter) Allow(ctx context.Context, namespace, actionType string) error {
+	policies := &v1alpha1.CounterMeasurePolicyList{}
+	if err := r.client.List(ctx, policies); err != nil {
+		return err
+	}
+
+	limits := make([]v1alpha1.ActionRateLimit, 0)
+	for _, policy := range policies.Items {
+		for _, limit := range policy.Spec.RateLimits {
+			if limit.ActionType == actionType {
+				limits = append(limits, limit)
+			}
+		}
+	}
+
+	if len(limits) == 0 {
+		return nil
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	now := time.Now()
+	clusterKey := actionType
+	namespaceKey := namespace + "/" + actionType
+
+	for _, limit := range limits {
+		since := now.Add(-limit.Window.Duration)
+
+		if limit.ClusterLimit != nil && r.count(clusterKey, since) >= int(*limit.ClusterLimit) {
+			return fmt.Errorf("cluster rate limit of %d %s actions per %s exhausted",
+				*limit.ClusterLimit, actionType, limit.Window.Duration)
+		}
+
+		if limit.NamespaceLimit != nil && r.count(namespaceKey, since) >= int(*limit.NamespaceLimit) {
+			return fmt.Errorf("namespace '%s' rate limit of %d %s actions per %s exhausted",
+				namespace, *limit.NamespaceLimit, actionType, limit.Window.Duration)
+		}
+	}
+
+	// forget the actions that have fallen out of every window
+	longest := time.Duration(0)
+	for _, limit := range limits {
+		if limit.Window.Duration > longest {
+			longest = limit.Window.Duration
+		}
+	}
+	r.prune(clusterKey, now.Add(-longest))
+	r.prune(namespaceKey, now.Add(-longest))
+
+	r.taken[clusterKey] = append(r.taken[clusterKey], now)
+	r.taken[namespaceKey] = append(r.taken[namespaceKey], now)
+
+	return nil
+}
+
+// count the number of actions taken after since
+func (r *RateLimiter) count(key string, since time.Time) int {
+	count := 0
+	for _, t := range r.taken[key] {
+		if t.After(since) {
+			count++
+		}
+	}
+	return count
+}
+
+// prune remove the actions taken before the cutoff
+func (r *RateLimiter) prune(key string, cutoff time.Time) {
+	kept := r.taken[key][:0]
+	for _, t := range r.taken[key] {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	r.taken[key] = kept
+}
@@ -0,0 +1,155 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	clusterLimit := int32(3)
+	namespaceLimit := int32(2)
+
+	policy := &v1alpha1.CounterMeasurePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: v1alpha1.CounterMeasurePolicySpec{
+			RateLimits: []v1alpha1.ActionRateLimit{
+				{
+					ActionType:     "restart",
+					Window:         metav1.Duration{Duration: time.Hour},
+					ClusterLimit:   &clusterLimit,
+					NamespaceLimit: &namespaceLimit,
+				},
+			},
+		},
+	}
+
+	limiter := NewRateLimiter(newRunsClient(policy))
+	ctx := context.TODO()
+
+	assert.NoError(t, limiter.Allow(ctx, "ns1", "restart"))
+	assert.NoError(t, limiter.Allow(ctx, "ns1", "restart"))
+	// the namespace budget is exhausted
+	assert.EqualError(t, limiter.Allow(ctx, "ns1", "restart"),
+		"namespace 'ns1' rate limit of 2 restart actions per 1h0m0s exhausted")
+
+	assert.NoError(t, limiter.Allow(ctx, "ns2", "restart"))
+	// the cluster budget is exhausted
+	assert.EqualError(t, limiter.Allow(ctx, "ns3", "restart"),
+		"cluster rate limit of 3 restart actions per 1h0m0s exhausted")
+
+	// other action types aren't limited
+	assert.NoError(t, limiter.Allow(ctx, "ns1", "delete"))
+}
+
+func TestRateLimiter_Window(t *testing.T) {
+	namespaceLimit := int32(1)
+
+	policy := &v1alpha1.CounterMeasurePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: v1alpha1.CounterMeasurePolicySpec{
+			RateLimits: []v1alpha1.ActionRateLimit{
+				{
+					ActionType:     "delete",
+					Window:         metav1.Duration{Duration: 50 * time.Millisecond},
+					NamespaceLimit: &namespaceLimit,
+				},
+			},
+		},
+	}
+
+	limiter := NewRateLimiter(newRunsClient(policy))
+	ctx := context.TODO()
+
+	assert.NoError(t, limiter.Allow(ctx, "ns1", "delete"))
+	assert.Error(t, limiter.Allow(ctx, "ns1", "delete"))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, limiter.Allow(ctx, "ns1", "delete"))
+}
+
+func TestInMemoryRunner_RateLimited(t *testing.T) {
+	namespaceLimit := int32(0)
+	policy := &v1alpha1.CounterMeasurePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: v1alpha1.CounterMeasurePolicySpec{
+			RateLimits: []v1alpha1.ActionRateLimit{
+				{
+					ActionType:     "mock",
+					Window:         metav1.Duration{Duration: time.Hour},
+					NamespaceLimit: &namespaceLimit,
+				},
+			},
+		},
+	}
+
+	k8sClient := newRunsClient(policy)
+	recorder := record.NewFakeRecorder(10)
+	runner := InMemoryRunner{&MockAction{}}
+
+	err := runner.Run(ActionContext{
+		Client:         k8sClient,
+		Recorder:       recorder,
+		CounterMeasure: v1alpha1.CounterMeasure{ObjectMeta: CreateObjectMeta("limited")},
+		RateLimiter:    NewRateLimiter(k8sClient),
+	}, events.Event{})
+
+	assert.Error(t, err)
+	assert.Contains(t, <-recorder.Events, "RateLimited")
+}
+
+func TestInMemoryRunner_RateLimitedClusterCounterMeasure(t *testing.T) {
+	namespaceLimit := int32(1)
+	policy := &v1alpha1.CounterMeasurePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: v1alpha1.CounterMeasurePolicySpec{
+			RateLimits: []v1alpha1.ActionRateLimit{
+				{
+					ActionType:     "mock",
+					Window:         metav1.Duration{Duration: time.Hour},
+					NamespaceLimit: &namespaceLimit,
+				},
+			},
+		},
+	}
+
+	deleteIn := func(namespace string) v1alpha1.Action {
+		return v1alpha1.Action{
+			Name: "delete-" + namespace,
+			Delete: &v1alpha1.DeleteSpec{
+				TargetObjectRef: v1alpha1.ObjectReference{Namespace: namespace, Name: "pod", Kind: "Pod", ApiVersion: "v1"},
+			},
+		}
+	}
+
+	// a cluster countermeasure has no namespace, the limit applies to the namespace of each target
+	cm := v1alpha1.CounterMeasure{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: v1alpha1.CounterMeasureSpec{
+			Actions: []v1alpha1.Action{deleteIn("ns1"), deleteIn("ns2")},
+		},
+	}
+
+	k8sClient := newRunsClient(policy)
+	recorder := record.NewFakeRecorder(10)
+	limiter := NewRateLimiter(k8sClient)
+	ctx := ActionContext{
+		Client:         k8sClient,
+		Recorder:       recorder,
+		CounterMeasure: cm,
+		RateLimiter:    limiter,
+	}
+
+	runner := InMemoryRunner{&MockAction{}, &MockAction{}}
+	assert.NoError(t, runner.Run(ctx, events.Event{}))
+
+	runner = InMemoryRunner{&MockAction{}}
+	ctx.CounterMeasure.Spec.Actions = []v1alpha1.Action{deleteIn("ns1")}
+	assert.True(t, IsRefused(runner.Run(ctx, events.Event{})))
+}
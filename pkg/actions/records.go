@@ -0,0 +1,58 @@
+package actions
+
+import (
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ToEventRecord copy an event into a record that can be stored on a custom resource.
+func ToEventRecord(event events.Event) v1alpha1.EventRecord {
+	record := v1alpha1.EventRecord{
+		Name: event.Name,
+	}
+
+	if !event.ActiveTime.IsZero() {
+		record.ActiveTime = &metav1.Time{Time: event.ActiveTime}
+	}
+
+	if event.Data != nil {
+		record.Data = make(map[string]string, len(*event.Data))
+		for k, v := range *event.Data {
+			record.Data[k] = v
+		}
+	}
+
+	if (event.Source != types.NamespacedName{}) {
+		record.Source = event.Source.String()
+		record.SourceKind = event.SourceKind
+	}
+
+	return record
+}
+
+// FromEventRecord re-create the event from a record stored on a custom resource.
+func FromEventRecord(record v1alpha1.EventRecord) events.Event {
+	data := make(events.EventData, len(record.Data))
+	for k, v := range record.Data {
+		data[k] = v
+	}
+
+	event := events.Event{
+		Name: record.Name,
+		Data: &data,
+	}
+
+	if record.ActiveTime != nil {
+		event.ActiveTime = record.ActiveTime.Time
+	}
+
+	if len(record.Source) > 0 {
+		event.Source = reconciler.SplitKey(record.Source)
+		event.SourceKind = record.SourceKind
+	}
+
+	return event
+}
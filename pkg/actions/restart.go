@@ -50,6 +50,10 @@ func (r *Restart) Perform(ctx context.Context, event events.Event) error {
 	object.SetGroupVersionKind(gvk)
 
 	target := r.spec.DeploymentRef
+	if err := r.checkNamespace(ctx, target.Namespace, event); err != nil {
+		return err
+	}
+
 	objectName := ObjectKeyFromTemplate(target.Namespace, target.Name, event)
 
 	// do the patch to the labels to force a restart
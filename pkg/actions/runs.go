@@ -0,0 +1,146 @@
+package actions
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultRunHistoryLimit the number of finished runs kept when the countermeasure doesn't set a limit
+const DefaultRunHistoryLimit = 10
+
+// runLabels the labels used to find the runs of a countermeasure for an event
+func runLabels(cm *v1alpha1.CounterMeasure, evt events.Event) map[string]string {
+	return map[string]string{
+		counterMeasureLabel(cm): cm.Name,
+		v1alpha1.EventKeyLabel:  evt.Key(),
+	}
+}
+
+// createRun creates a CounterMeasureRun owned by the countermeasure recording the event
+// and the actions planned for it.
+func (m *Manager) createRun(ctx context.Context, cm *v1alpha1.CounterMeasure, evt events.Event,
+	runner ActionRunner, status v1alpha1.CounterMeasureRunStatus, expiresAt *metav1.Time) (*v1alpha1.CounterMeasureRun, error) {
+
+	namespace, err := m.runNamespace(cm)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &v1alpha1.CounterMeasureRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: cm.Name + "-",
+			Namespace:    namespace,
+			Labels:       runLabels(cm, evt),
+		},
+		Spec: v1alpha1.CounterMeasureRunSpec{
+			CounterMeasure: cm.Name,
+			ClusterScoped:  isClusterScoped(cm),
+			Event:          ToEventRecord(evt),
+			DryRun:         cm.Spec.DryRun,
+			PlannedActions: runner.Plan(evt),
+			Actions:        cm.Spec.Actions,
+			ExpiresAt:      expiresAt,
+		},
+	}
+	setRunOwner(cm, run)
+
+	if err := m.client.Create(ctx, run); err != nil {
+		return nil, err
+	}
+
+	// the status is a subresource so it can't be set on create
+	err = UpdateRunStatus(ctx, m.client, client.ObjectKeyFromObject(run), func(s *v1alpha1.CounterMeasureRunStatus) {
+		*s = status
+	})
+
+	return run, err
+}
+
+// completeRun records the outcome of the run and prunes the runs exceeding the history limit.
+func (m *Manager) completeRun(ctx context.Context, cm *v1alpha1.CounterMeasure, key types.NamespacedName, runErr error) {
+	err := UpdateRunStatus(ctx, m.client, key, func(status *v1alpha1.CounterMeasureRunStatus) {
+		status.Phase = v1alpha1.Succeeded
+		status.Message = ""
+		if runErr != nil {
+			status.Phase = v1alpha1.Failed
+			status.Message = runErr.Error()
+		}
+		status.CompletionTime = &metav1.Time{Time: time.Now()}
+	})
+
+	if err != nil {
+		managerLog.Error(err, "failed to update countermeasure run status",
+			"name", key.Name, "namespace", key.Namespace)
+	}
+
+	if err := m.pruneRuns(ctx, cm); err != nil {
+		managerLog.Error(err, "failed to prune countermeasure runs",
+			"name", cm.Name, "namespace", cm.Namespace)
+	}
+}
+
+// pruneRuns delete the oldest finished runs of a countermeasure exceeding the history limit.
+func (m *Manager) pruneRuns(ctx context.Context, cm *v1alpha1.CounterMeasure) error {
+	limit := DefaultRunHistoryLimit
+	if cm.Spec.RunHistoryLimit != nil {
+		limit = int(*cm.Spec.RunHistoryLimit)
+	}
+
+	namespace, err := m.runNamespace(cm)
+	if err != nil {
+		return err
+	}
+
+	runs := &v1alpha1.CounterMeasureRunList{}
+	err = m.client.List(ctx, runs, client.InNamespace(namespace),
+		client.MatchingLabels{counterMeasureLabel(cm): cm.Name})
+	if err != nil {
+		return err
+	}
+
+	finished := make([]v1alpha1.CounterMeasureRun, 0, len(runs.Items))
+	for _, run := range runs.Items {
+		if run.Status.IsFinished() {
+			finished = append(finished, run)
+		}
+	}
+
+	if len(finished) <= limit {
+		return nil
+	}
+
+	// newest first
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[j].CreationTimestamp.Before(&finished[i].CreationTimestamp)
+	})
+
+	for i := limit; i < len(finished); i++ {
+		if err := m.client.Delete(ctx, &finished[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateRunStatus re-fetches the CounterMeasureRun and applies the mutation to its status.
+func UpdateRunStatus(ctx context.Context, c client.Client, key types.NamespacedName, mutate func(*v1alpha1.CounterMeasureRunStatus)) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		run := &v1alpha1.CounterMeasureRun{}
+		if err := c.Get(ctx, key, run); err != nil {
+			return err
+		}
+
+		mutate(&run.Status)
+		return c.Status().Update(ctx, run)
+	})
+}
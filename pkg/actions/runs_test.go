@@ -0,0 +1,147 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/actions/state"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestManager_ExecuteRecordsRun(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("recorded"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			OnEvent: v1alpha1.OnEventSpec{
+				EventName: "event1",
+			},
+			DryRun: true,
+			Actions: []v1alpha1.Action{
+				{
+					Name: "ok",
+					Delete: &v1alpha1.DeleteSpec{
+						TargetObjectRef: v1alpha1.ObjectReference{Namespace: "ns", Name: "{{ .Data.pod }}", Kind: "Pod", ApiVersion: "v1"},
+					},
+				},
+				{
+					Name: "fails",
+					Delete: &v1alpha1.DeleteSpec{
+						TargetObjectRef: v1alpha1.ObjectReference{Namespace: "ns", Name: "fail", Kind: "Pod", ApiVersion: "v1"},
+					},
+				},
+			},
+		},
+	}
+
+	k8sClient := newRunsClient(cm.DeepCopy())
+
+	registry := Registry{}
+	registry.RegisterAction(v1alpha1.DeleteSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
+		return &failingAction{NewDeleteFromBase(NewBase(c.Client, spec, dryRun), *spec.Delete)}
+	})
+
+	mgr := &Manager{
+		client:         k8sClient,
+		recorder:       record.NewFakeRecorder(10),
+		ActionRegistry: registry,
+		state:          state.NewState(),
+	}
+
+	activeTime := time.Now().Truncate(time.Second)
+	event := events.Event{
+		Name:       "event1",
+		ActiveTime: activeTime,
+		Data:       &events.EventData{"pod": "my-pod"},
+	}
+	mgr.execute(manager.ToKey(cm.ObjectMeta), cm, event, nil)
+
+	runs := &v1alpha1.CounterMeasureRunList{}
+	assert.NoError(t, k8sClient.List(context.TODO(), runs, client.InNamespace(cm.Namespace)))
+	assert.Equal(t, 1, len(runs.Items))
+
+	run := runs.Items[0]
+	assert.Equal(t, cm.Name, run.OwnerReferences[0].Name)
+	assert.Equal(t, "event1", run.Spec.Event.Name)
+	assert.Equal(t, "my-pod", run.Spec.Event.Data["pod"])
+	assert.True(t, activeTime.Equal(run.Spec.Event.ActiveTime.Time))
+	assert.True(t, run.Spec.DryRun)
+	assert.Equal(t, v1alpha1.Failed, run.Status.Phase)
+	assert.Equal(t, "failed to delete 'fail'", run.Status.Message)
+	assert.NotNil(t, run.Status.StartTime)
+	assert.NotNil(t, run.Status.CompletionTime)
+
+	assert.Equal(t, 2, len(run.Status.Actions))
+	assert.Equal(t, "pod: 'ns/my-pod'", run.Status.Actions[0].Target)
+	assert.Equal(t, v1alpha1.ActionSucceeded, run.Status.Actions[0].Outcome)
+	assert.NotNil(t, run.Status.Actions[0].EndTime)
+	assert.Equal(t, v1alpha1.ActionFailed, run.Status.Actions[1].Outcome)
+	assert.Equal(t, "failed to delete 'fail'", run.Status.Actions[1].Error)
+}
+
+func TestManager_PruneRuns(t *testing.T) {
+	limit := int32(2)
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("pruned"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			RunHistoryLimit: &limit,
+		},
+	}
+
+	objs := []client.Object{cm.DeepCopy()}
+	phases := []v1alpha1.RunPhase{v1alpha1.Succeeded, v1alpha1.Failed, v1alpha1.Expired, v1alpha1.Succeeded, v1alpha1.PendingApproval}
+	for i, phase := range phases {
+		objs = append(objs, &v1alpha1.CounterMeasureRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("pruned-%d", i),
+				Namespace:         cm.Namespace,
+				Labels:            map[string]string{v1alpha1.CounterMeasureLabel: cm.Name},
+				CreationTimestamp: metav1.Time{Time: time.Now().Add(time.Duration(i) * time.Minute)},
+			},
+			Status: v1alpha1.CounterMeasureRunStatus{Phase: phase},
+		})
+	}
+
+	mgr := &Manager{client: newRunsClient(objs...)}
+	assert.NoError(t, mgr.pruneRuns(context.TODO(), cm))
+
+	runs := &v1alpha1.CounterMeasureRunList{}
+	assert.NoError(t, mgr.client.List(context.TODO(), runs, client.InNamespace(cm.Namespace)))
+
+	names := make([]string, 0)
+	for _, run := range runs.Items {
+		names = append(names, run.Name)
+	}
+	// the two newest finished runs are kept along with the run pending approval
+	assert.ElementsMatch(t, []string{"pruned-2", "pruned-3", "pruned-4"}, names)
+}
+
+func newRunsClient(objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	clientgoscheme.AddToScheme(s)
+	v1alpha1.AddToScheme(s)
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+// failingAction fails when the target object is named 'fail'
+type failingAction struct {
+	*Delete
+}
+
+func (f *failingAction) Perform(ctx context.Context, event events.Event) error {
+	if f.spec.TargetObjectRef.Name == "fail" {
+		return errors.New("failed to delete 'fail'")
+	}
+	return f.Delete.Perform(ctx, event)
+}
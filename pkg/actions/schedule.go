@@ -0,0 +1,158 @@
+package actions
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InSchedule checks if the time is inside an active window and outside of every blackout window
+// of the schedule. A countermeasure without a schedule is always active.
+func InSchedule(schedule *v1alpha1.ScheduleSpec, now time.Time) (bool, error) {
+	if schedule == nil {
+		return true, nil
+	}
+
+	location := time.UTC
+	if len(schedule.Timezone) > 0 {
+		var err error
+		if location, err = time.LoadLocation(schedule.Timezone); err != nil {
+			return false, err
+		}
+	}
+	now = now.In(location)
+
+	for _, window := range schedule.BlackoutWindows {
+		inWindow, err := inTimeWindow(window, now)
+		if err != nil || inWindow {
+			return false, err
+		}
+	}
+
+	if len(schedule.ActiveWindows) == 0 {
+		return true, nil
+	}
+
+	for _, window := range schedule.ActiveWindows {
+		inWindow, err := inTimeWindow(window, now)
+		if err != nil || inWindow {
+			return inWindow, err
+		}
+	}
+
+	return false, nil
+}
+
+// inTimeWindow checks if the window started within its duration before now.
+func inTimeWindow(window v1alpha1.TimeWindow, now time.Time) (bool, error) {
+	schedule, err := cron.ParseStandard(window.Start)
+	if err != nil {
+		return false, err
+	}
+
+	start := schedule.Next(now.Add(-window.Duration.Duration))
+	return !start.After(now), nil
+}
+
+// outsideScheduleMessage the message of the runs skipped for being outside of the schedule
+const outsideScheduleMessage = "Event is outside of the countermeasure schedule."
+
+// applySchedule returns the countermeasure to execute for the event according to its schedule, which
+// is a dry run copy outside of the schedule when configured to fall back to a dry run. Returns false
+// when the event is skipped, recording a skipped run once per event, or once per suppression window
+// when the countermeasure has a suppression duration.
+func (m *Manager) applySchedule(cm *v1alpha1.CounterMeasure, evt events.Event) (*v1alpha1.CounterMeasure, bool) {
+	active, err := InSchedule(cm.Spec.Schedule, time.Now())
+	if err != nil {
+		utilruntime.HandleError(err)
+		m.recorder.Event(cm, "Warning", "ScheduleError", err.Error())
+		return nil, false
+	}
+
+	if active {
+		return cm, true
+	}
+
+	if cm.Spec.Schedule.OutsideSchedule == v1alpha1.OutsideScheduleDryRun {
+		dryRun := cm.DeepCopy()
+		dryRun.Spec.DryRun = true
+		return dryRun, true
+	}
+
+	ctx := context.Background()
+	recorded, err := m.isSkipRecorded(ctx, cm, evt)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return nil, false
+	}
+
+	if recorded {
+		return nil, false
+	}
+
+	m.recorder.Event(cm, "Normal", "Skipping", outsideScheduleMessage)
+
+	actionRunner, err := m.ActionRegistry.NewRunner(ActionContext{
+		Client:         m.client,
+		RestConfig:     m.restConfig,
+		Recorder:       m.recorder,
+		CounterMeasure: *cm,
+	})
+	if err != nil {
+		utilruntime.HandleError(err)
+		return nil, false
+	}
+
+	status := v1alpha1.CounterMeasureRunStatus{
+		Phase:          v1alpha1.Skipped,
+		Message:        outsideScheduleMessage,
+		CompletionTime: &metav1.Time{Time: time.Now()},
+	}
+	if _, err := m.createRun(ctx, cm, evt, actionRunner, status, nil); err != nil {
+		managerLog.Error(err, "failed to create countermeasure run", "name", cm.Name, "namespace", cm.Namespace)
+	}
+
+	if err := m.pruneRuns(ctx, cm); err != nil {
+		managerLog.Error(err, "failed to prune countermeasure runs", "name", cm.Name, "namespace", cm.Namespace)
+	}
+
+	return nil, false
+}
+
+// isSkipRecorded checks if a run skipped for being outside of the schedule was already recorded for
+// the event, within the suppression duration of the countermeasure if it has one.
+func (m *Manager) isSkipRecorded(ctx context.Context, cm *v1alpha1.CounterMeasure, evt events.Event) (bool, error) {
+	namespace, err := m.runNamespace(cm)
+	if err != nil {
+		return false, err
+	}
+
+	runs := &v1alpha1.CounterMeasureRunList{}
+	err = m.client.List(ctx, runs, client.InNamespace(namespace), client.MatchingLabels(runLabels(cm, evt)))
+	if err != nil {
+		return false, err
+	}
+
+	var since time.Time
+	if policy := cm.Spec.OnEvent.SuppressionPolicy; policy != nil && policy.Duration != nil {
+		since = time.Now().Add(-policy.Duration.Duration)
+	}
+
+	for _, run := range runs.Items {
+		if run.Status.Phase != v1alpha1.Skipped || run.Status.Message != outsideScheduleMessage {
+			continue
+		}
+
+		if run.Status.CompletionTime != nil && run.Status.CompletionTime.Time.After(since) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
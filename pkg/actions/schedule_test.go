@@ -0,0 +1,132 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestInSchedule(t *testing.T) {
+	nights := v1alpha1.TimeWindow{Start: "0 22 * * *", Duration: metav1.Duration{Duration: 8 * time.Hour}}
+	tradingHours := v1alpha1.TimeWindow{Start: "30 9 * * 1-5", Duration: metav1.Duration{Duration: 390 * time.Minute}}
+
+	ny, _ := time.LoadLocation("America/New_York")
+
+	tests := []struct {
+		name     string
+		schedule *v1alpha1.ScheduleSpec
+		now      time.Time
+		want     bool
+	}{
+		{
+			name: "no schedule",
+			now:  time.Now(),
+			want: true,
+		},
+		{
+			name:     "inside the active window after midnight",
+			schedule: &v1alpha1.ScheduleSpec{ActiveWindows: []v1alpha1.TimeWindow{nights}},
+			now:      time.Date(2022, 11, 1, 3, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "outside the active window",
+			schedule: &v1alpha1.ScheduleSpec{ActiveWindows: []v1alpha1.TimeWindow{nights}},
+			now:      time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name: "inside a blackout window in the timezone",
+			schedule: &v1alpha1.ScheduleSpec{
+				Timezone:        "America/New_York",
+				BlackoutWindows: []v1alpha1.TimeWindow{tradingHours},
+			},
+			// a Tuesday
+			now:  time.Date(2022, 11, 1, 10, 0, 0, 0, ny),
+			want: false,
+		},
+		{
+			name: "outside a blackout window on the weekend",
+			schedule: &v1alpha1.ScheduleSpec{
+				Timezone:        "America/New_York",
+				BlackoutWindows: []v1alpha1.TimeWindow{tradingHours},
+			},
+			// a Saturday
+			now:  time.Date(2022, 11, 5, 10, 0, 0, 0, ny),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InSchedule(tt.schedule, tt.now)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestManager_ApplySchedule(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("scheduled"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			Schedule: &v1alpha1.ScheduleSpec{
+				// a blackout window starting every minute, so it's always in effect
+				BlackoutWindows: []v1alpha1.TimeWindow{
+					{Start: "* * * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				},
+			},
+		},
+	}
+
+	k8sClient := newRunsClient(cm.DeepCopy())
+	mgr := &Manager{
+		client:   k8sClient,
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	// the skip is recorded once for the event
+	for i := 0; i < 3; i++ {
+		_, ok := mgr.applySchedule(cm, events.Event{Name: "event1"})
+		assert.False(t, ok)
+	}
+
+	runs := &v1alpha1.CounterMeasureRunList{}
+	assert.NoError(t, k8sClient.List(context.TODO(), runs, client.InNamespace(cm.Namespace)))
+	assert.Equal(t, 1, len(runs.Items))
+	assert.Equal(t, v1alpha1.Skipped, runs.Items[0].Status.Phase)
+
+	// with a suppression duration the skip is recorded again once the window has passed
+	cm.Spec.OnEvent.SuppressionPolicy = &v1alpha1.SuppressionPolicySpec{Duration: &metav1.Duration{Duration: time.Hour}}
+	_, ok := mgr.applySchedule(cm, events.Event{Name: "event1"})
+	assert.False(t, ok)
+	assert.NoError(t, k8sClient.List(context.TODO(), runs, client.InNamespace(cm.Namespace)))
+	assert.Equal(t, 1, len(runs.Items))
+
+	cm.Spec.OnEvent.SuppressionPolicy.Duration.Duration = 0
+	_, ok = mgr.applySchedule(cm, events.Event{Name: "event1"})
+	assert.False(t, ok)
+	assert.NoError(t, k8sClient.List(context.TODO(), runs, client.InNamespace(cm.Namespace)))
+	assert.Equal(t, 2, len(runs.Items))
+
+	// the skipped runs are pruned to the history limit
+	limit := int32(1)
+	cm.Spec.RunHistoryLimit = &limit
+	_, ok = mgr.applySchedule(cm, events.Event{Name: "event2"})
+	assert.False(t, ok)
+	assert.NoError(t, k8sClient.List(context.TODO(), runs, client.InNamespace(cm.Namespace)))
+	assert.Equal(t, 1, len(runs.Items))
+
+	cm.Spec.Schedule.OutsideSchedule = v1alpha1.OutsideScheduleDryRun
+	dryRun, ok := mgr.applySchedule(cm, events.Event{Name: "event1"})
+	assert.True(t, ok)
+	assert.True(t, dryRun.Spec.DryRun)
+	assert.False(t, cm.Spec.DryRun)
+}
@@ -1,7 +1,9 @@
 package state
 
 import (
+	"bytes"
 	"sync"
+	"text/template"
 	"time"
 
 	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
@@ -48,12 +50,18 @@ func (s *ActionState) IsRunning(key manager.ObjectKey) bool {
 	return entry.running
 }
 
-// CounterMeasureStart record the start of a countermeasure for an event.
-func (s *ActionState) CounterMeasureStart(event events.Event, key manager.ObjectKey) {
+// CounterMeasureStart record the start of a countermeasure for an event, the targets are
+// the rendered targets of the actions used by policies suppressing by target.
+func (s *ActionState) CounterMeasureStart(event events.Event, key manager.ObjectKey, targets ...string) {
 	s.measuresMux.Lock()
 	defer s.measuresMux.Unlock()
 
-	entry := s.counterMeasures[key]
+	entry, ok := s.counterMeasures[key]
+	if !ok {
+		// the countermeasure was removed or updated since the event was received
+		return
+	}
+
 	entry.Lock()
 	entry.running = true
 
@@ -61,7 +69,10 @@ func (s *ActionState) CounterMeasureStart(event events.Event, key manager.Object
 	// with the same key are suppressed.
 	policy := entry.Countermeasure.Spec.OnEvent.SuppressionPolicy
 	if policy != nil && policy.Duration != nil {
-		entry.lastRuns[event.Key()] = time.Now().Add(policy.Duration.Duration)
+		until := time.Now().Add(policy.Duration.Duration)
+		for _, k := range entry.suppressionKeys(event, targets) {
+			entry.lastRuns[k] = until
+		}
 	}
 	entry.Unlock()
 }
@@ -70,7 +81,10 @@ func (s *ActionState) CounterMeasureStart(event events.Event, key manager.Object
 func (s *ActionState) CounterMeasureEnd(event events.Event, key manager.ObjectKey) {
 	s.measuresMux.Lock()
 	defer s.measuresMux.Unlock()
-	entry := s.counterMeasures[key]
+	entry, ok := s.counterMeasures[key]
+	if !ok {
+		return
+	}
 
 	entry.Lock()
 	entry.running = false
@@ -103,6 +117,13 @@ func (s *ActionState) Add(countermeasure *v1alpha1.CounterMeasure) error {
 		lastRuns:       make(map[string]time.Time),
 	}
 
+	// rehydrate the suppressions persisted before the operator was restarted or the
+	// countermeasure was updated.
+	for _, suppression := range countermeasure.Status.Suppressions {
+		entry.lastRuns[suppression.EventKey] = suppression.Until.Time
+	}
+	entry.clearExpiredLastRuns()
+
 	s.measuresMux.Lock()
 	defer s.measuresMux.Unlock()
 
@@ -142,21 +163,83 @@ func (s *ActionState) GetCounterMeasure(key manager.ObjectKey) *Entry {
 	return entry
 }
 
-func (e *Entry) IsSuppressed(event events.Event) bool {
+func (e *Entry) IsSuppressed(event events.Event, targets ...string) bool {
 	e.Lock()
 	defer e.Unlock()
 
-	var (
-		suppressed = false
-	)
+	if e.running {
+		return true
+	}
 
-	suppressedUntil, ok := e.lastRuns[event.Key()]
-	if ok {
+	now := time.Now()
+	for _, k := range e.suppressionKeys(event, targets) {
 		// we have a suppression time so check if it's expired
-		suppressed = suppressedUntil.After(time.Now())
+		if suppressedUntil, ok := e.lastRuns[k]; ok && suppressedUntil.After(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SuppressionKeys returns the keys identifying duplicates of the event according to the
+// suppression policy.
+func (e *Entry) SuppressionKeys(event events.Event, targets ...string) []string {
+	e.Lock()
+	defer e.Unlock()
+
+	return e.suppressionKeys(event, targets)
+}
+
+// SuppressedUntil returns the time until which the suppression key is suppressed, if it is.
+func (e *Entry) SuppressedUntil(key string) (time.Time, bool) {
+	e.Lock()
+	defer e.Unlock()
+
+	suppressedUntil, ok := e.lastRuns[key]
+	return suppressedUntil, ok
+}
+
+func (e *Entry) suppressionKeys(event events.Event, targets []string) []string {
+	policy := e.Countermeasure.Spec.OnEvent.SuppressionPolicy
+	if policy == nil {
+		return []string{event.Key()}
+	}
+
+	if policy.Mode == v1alpha1.SuppressByTarget {
+		// without targets, e.g. only waitFor actions, the event itself is suppressed
+		if len(targets) == 0 {
+			return []string{event.Key()}
+		}
+
+		keys := make([]string, 0, len(targets))
+		for _, target := range targets {
+			keys = append(keys, "target/"+target)
+		}
+		return keys
+	}
+
+	data := make(events.EventData)
+	if len(policy.GroupBy) > 0 {
+		for _, label := range policy.GroupBy {
+			if event.Data != nil {
+				data[label] = event.Data.Get(label)
+			}
+		}
+	} else if len(policy.GroupByTemplate) > 0 {
+		tmpl, err := template.New("").Parse(policy.GroupByTemplate)
+		if err != nil {
+			return []string{event.Key()}
+		}
+
+		var buf bytes.Buffer
+		tmpl.Execute(&buf, event)
+		data["group"] = buf.String()
+	} else {
+		return []string{event.Key()}
 	}
 
-	return e.running || suppressed
+	return []string{events.Event{Name: event.Name, Data: &data}.Key()}
 }
 
 func (e *Entry) clearExpiredLastRuns() {
@@ -185,6 +185,110 @@ func TestActionState_CounterMeasureEvents(t *testing.T) {
 	assert.True(t, entry.IsSuppressed(event))
 }
 
+func TestActionState_AddRehydratesSuppressions(t *testing.T) {
+	state := NewState()
+
+	key := Key()
+	suppressed := events.Event{Name: "event1", Data: &events.EventData{"pod": "a"}}
+	expired := events.Event{Name: "event1", Data: &events.EventData{"pod": "b"}}
+
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: v1.ObjectMeta{
+			Name:       key.Name,
+			Namespace:  key.Namespace,
+			Generation: key.Generation,
+		},
+		Spec: v1alpha1.CounterMeasureSpec{
+			OnEvent: v1alpha1.OnEventSpec{
+				EventName: "event1",
+			},
+		},
+		Status: v1alpha1.CounterMeasureStatus{
+			Suppressions: []v1alpha1.Suppression{
+				{EventKey: suppressed.Key(), Until: v1.Time{Time: time.Now().Add(time.Minute)}},
+				{EventKey: expired.Key(), Until: v1.Time{Time: time.Now().Add(-time.Minute)}},
+			},
+		},
+	}
+
+	state.Add(cm)
+
+	entry := state.GetCounterMeasure(key)
+	assert.True(t, entry.IsSuppressed(suppressed))
+	assert.False(t, entry.IsSuppressed(expired))
+
+	_, ok := entry.SuppressedUntil(expired.Key())
+	assert.False(t, ok)
+}
+
+func TestEntry_SuppressionKeys(t *testing.T) {
+	first := events.Event{Name: "event1", Data: &events.EventData{"pod": "a", "instance": "10.0.0.1"}}
+	second := events.Event{Name: "event1", Data: &events.EventData{"pod": "a", "instance": "10.0.0.2"}}
+
+	tests := []struct {
+		name     string
+		policy   *v1alpha1.SuppressionPolicySpec
+		targets  []string
+		wantSame bool
+	}{
+		{
+			name:     "all labels",
+			policy:   &v1alpha1.SuppressionPolicySpec{},
+			wantSame: false,
+		},
+		{
+			name:     "group by labels",
+			policy:   &v1alpha1.SuppressionPolicySpec{GroupBy: []string{"pod"}},
+			wantSame: true,
+		},
+		{
+			name:     "group by template",
+			policy:   &v1alpha1.SuppressionPolicySpec{GroupByTemplate: "{{ .Data.pod }}"},
+			wantSame: true,
+		},
+		{
+			name:     "by target",
+			policy:   &v1alpha1.SuppressionPolicySpec{Mode: v1alpha1.SuppressByTarget},
+			targets:  []string{"deployment: 'ns/app'"},
+			wantSame: true,
+		},
+		{
+			name:     "by target without targets",
+			policy:   &v1alpha1.SuppressionPolicySpec{Mode: v1alpha1.SuppressByTarget},
+			wantSame: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.policy.Duration = &v1.Duration{Duration: time.Minute}
+
+			state := NewState()
+			key := Key()
+			state.Add(&v1alpha1.CounterMeasure{
+				ObjectMeta: v1.ObjectMeta{
+					Name:       key.Name,
+					Namespace:  key.Namespace,
+					Generation: key.Generation,
+				},
+				Spec: v1alpha1.CounterMeasureSpec{
+					OnEvent: v1alpha1.OnEventSpec{
+						EventName:         "event1",
+						SuppressionPolicy: tt.policy,
+					},
+				},
+			})
+
+			entry := state.GetCounterMeasure(key)
+			state.CounterMeasureStart(first, key, tt.targets...)
+			state.CounterMeasureEnd(first, key)
+
+			assert.True(t, entry.IsSuppressed(first, tt.targets...))
+			assert.Equal(t, tt.wantSame, entry.IsSuppressed(second, tt.targets...))
+		})
+	}
+}
+
 func Key() manager.ObjectKey {
 	return manager.ObjectKey{
 		NamespacedName: types.NamespacedName{Namespace: "ns", Name: "name"},
@@ -0,0 +1,42 @@
+package actions
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// addSuppression store the suppression deadline of an event on the countermeasure status so
+// it's restored when the countermeasure is added again after an operator restart. Expired
+// suppressions are dropped at the same time.
+func addSuppression(ctx context.Context, c client.Client, key types.NamespacedName, eventKey string, until time.Time) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		cm, err := getCounterMeasure(ctx, c, key)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		now := time.Now()
+		suppressions := make([]v1alpha1.Suppression, 0, len(cm.Status.Suppressions)+1)
+		for _, s := range cm.Status.Suppressions {
+			if s.EventKey != eventKey && s.Until.After(now) {
+				suppressions = append(suppressions, s)
+			}
+		}
+
+		cm.Status.Suppressions = append(suppressions, v1alpha1.Suppression{
+			EventKey: eventKey,
+			Until:    metav1.Time{Time: until},
+		})
+		return updateCounterMeasureStatus(ctx, c, cm)
+	})
+}
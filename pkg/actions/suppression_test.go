@@ -0,0 +1,40 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestAddSuppression(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("suppressed"),
+		Status: v1alpha1.CounterMeasureStatus{
+			Suppressions: []v1alpha1.Suppression{
+				{EventKey: "expired", Until: metav1.Time{Time: time.Now().Add(-time.Minute)}},
+				{EventKey: "active", Until: metav1.Time{Time: time.Now().Add(time.Minute)}},
+				{EventKey: "event", Until: metav1.Time{Time: time.Now().Add(time.Minute)}},
+			},
+		},
+	}
+
+	k8sClient := newRunsClient(cm.DeepCopy())
+	until := time.Now().Add(time.Hour).Truncate(time.Second)
+	assert.NoError(t, addSuppression(context.TODO(), k8sClient, client.ObjectKeyFromObject(cm), "event", until))
+
+	updated := &v1alpha1.CounterMeasure{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cm), updated))
+
+	keys := make(map[string]time.Time)
+	for _, s := range updated.Status.Suppressions {
+		keys[s.EventKey] = s.Until.Time
+	}
+	assert.Equal(t, 2, len(keys))
+	assert.Contains(t, keys, "active")
+	assert.True(t, until.Equal(keys["event"]))
+}
@@ -0,0 +1,42 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveTemplate expands the actions of the CounterMeasureTemplate referenced by the countermeasure
+// into its spec, returning the template that was expanded, or nil when there's no reference.
+func ResolveTemplate(ctx context.Context, c client.Reader, cm *v1alpha1.CounterMeasure) (*v1alpha1.CounterMeasureTemplate, error) {
+	ref := cm.Spec.Template
+	if ref == nil {
+		return nil, nil
+	}
+
+	key := client.ObjectKey{Namespace: cm.Namespace, Name: ref.Name}
+	if isClusterScoped(cm) {
+		key.Namespace = ref.Namespace
+	}
+
+	if key.Namespace == "" {
+		return nil, fmt.Errorf("template '%s' requires a namespace", ref.Name)
+	}
+
+	template := &v1alpha1.CounterMeasureTemplate{}
+	if err := c.Get(ctx, key, template); err != nil {
+		return nil, fmt.Errorf("unable to get template '%s': %w", key, err)
+	}
+
+	actions, err := template.Expand(ref.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	cm.Spec.Actions = actions
+	cm.Spec.Template = nil
+
+	return template, nil
+}
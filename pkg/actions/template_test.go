@@ -0,0 +1,81 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResolveTemplate(t *testing.T) {
+	container := "app"
+	template := &v1alpha1.CounterMeasureTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "restart", Namespace: "ns"},
+		Spec: v1alpha1.CounterMeasureTemplateSpec{
+			Parameters: []v1alpha1.TemplateParameter{
+				{Name: "deployment"},
+				{Name: "container", Default: &container},
+			},
+			Actions: []v1alpha1.Action{
+				{
+					Name: "debug",
+					Debug: &v1alpha1.DebugSpec{
+						PodRef: v1alpha1.PodReference{
+							Namespace: "{{ .Data.namespace }}",
+							Name:      "{{ .Data.pod }}",
+							Container: "$(params.container)",
+						},
+					},
+				},
+				{
+					Name: "restart",
+					Restart: &v1alpha1.RestartSpec{
+						DeploymentRef: v1alpha1.DeploymentReference{
+							Namespace: "{{ .Data.namespace }}",
+							Name:      "$(params.deployment)",
+						},
+					},
+				},
+			},
+		},
+	}
+	c := newRunsClient(template)
+
+	newCounterMeasure := func(params map[string]string) *v1alpha1.CounterMeasure {
+		return &v1alpha1.CounterMeasure{
+			ObjectMeta: CreateObjectMeta("templated"),
+			Spec: v1alpha1.CounterMeasureSpec{
+				Template: &v1alpha1.TemplateReference{Name: "restart", Parameters: params},
+			},
+		}
+	}
+
+	cm := newCounterMeasure(map[string]string{"deployment": `web "frontend"`})
+	resolved, err := ResolveTemplate(context.TODO(), c, cm)
+	assert.NoError(t, err)
+	assert.Equal(t, "restart", resolved.Name)
+	assert.Nil(t, cm.Spec.Template)
+	assert.Equal(t, 2, len(cm.Spec.Actions))
+	assert.Equal(t, "app", cm.Spec.Actions[0].Debug.PodRef.Container)
+	assert.Equal(t, "{{ .Data.pod }}", cm.Spec.Actions[0].Debug.PodRef.Name)
+	assert.Equal(t, `web "frontend"`, cm.Spec.Actions[1].Restart.DeploymentRef.Name)
+
+	_, err = ResolveTemplate(context.TODO(), c, newCounterMeasure(nil))
+	assert.ErrorContains(t, err, "requires the parameters: deployment")
+
+	_, err = ResolveTemplate(context.TODO(), c, newCounterMeasure(map[string]string{"deployment": "web", "replicas": "2"}))
+	assert.ErrorContains(t, err, "has no parameters: replicas")
+
+	missing := newCounterMeasure(nil)
+	missing.Spec.Template.Name = "missing"
+	_, err = ResolveTemplate(context.TODO(), c, missing)
+	assert.Error(t, err)
+
+	// a countermeasure without a template is left as is
+	plain := &v1alpha1.CounterMeasure{ObjectMeta: CreateObjectMeta("plain")}
+	resolved, err = ResolveTemplate(context.TODO(), c, plain)
+	assert.NoError(t, err)
+	assert.Nil(t, resolved)
+}
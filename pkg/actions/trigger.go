@@ -0,0 +1,86 @@
+package actions
+
+import (
+	"context"
+	"path"
+	"sort"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TriggerDataKey the EventData key holding the name of the CounterMeasureTrigger that injected the
+// event, it makes the event of every trigger unique so the runs created for it can be found by its key.
+const TriggerDataKey = "trigger"
+
+// TriggerTopic the topic every countermeasure subscribes to besides the name of its event, the events
+// of the CounterMeasureTriggers naming the countermeasure are published to it so no other countermeasure
+// receives them. The topic has no ':' so it never matches the subscriptions to an event name.
+func TriggerTopic(counterMeasure types.NamespacedName) string {
+	return path.Join(v1alpha1.GroupVersion.Group, "trigger", counterMeasure.Namespace, counterMeasure.Name)
+}
+
+// NewTriggerEvent the event injected by the trigger, with the trigger as its source.
+func NewTriggerEvent(trigger *v1alpha1.CounterMeasureTrigger, eventName string) events.Event {
+	data := make(events.EventData, len(trigger.Spec.Data)+1)
+	for k, v := range trigger.Spec.Data {
+		data[k] = v
+	}
+	data[TriggerDataKey] = trigger.Name
+
+	return events.Event{
+		Name:       eventName,
+		ActiveTime: time.Now(),
+		Data:       &data,
+		Source:     client.ObjectKeyFromObject(trigger),
+		SourceKind: "CounterMeasureTrigger",
+	}
+}
+
+// ListeningCounterMeasures the CounterMeasures in the namespace listening for the event name, the events
+// of the triggers that don't name a countermeasure are delivered only to them.
+func ListeningCounterMeasures(ctx context.Context, c client.Reader, namespace, eventName string) ([]v1alpha1.CounterMeasure, error) {
+	list := &v1alpha1.CounterMeasureList{}
+	if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	listening := make([]v1alpha1.CounterMeasure, 0)
+	for _, cm := range list.Items {
+		if cm.Spec.OnEvent.EventName == eventName {
+			listening = append(listening, cm)
+		}
+	}
+	return listening, nil
+}
+
+// TriggeredRuns the CounterMeasureRuns created for the event injected by the trigger, oldest first.
+func TriggeredRuns(ctx context.Context, c client.Reader, trigger *v1alpha1.CounterMeasureTrigger) ([]v1alpha1.TriggeredRun, error) {
+	if len(trigger.Status.EventKey) == 0 {
+		return nil, nil
+	}
+
+	list := &v1alpha1.CounterMeasureRunList{}
+	err := c.List(ctx, list, client.InNamespace(trigger.Namespace),
+		client.MatchingLabels{v1alpha1.EventKeyLabel: trigger.Status.EventKey})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].CreationTimestamp.Before(&list.Items[j].CreationTimestamp)
+	})
+
+	runs := make([]v1alpha1.TriggeredRun, 0, len(list.Items))
+	for _, run := range list.Items {
+		runs = append(runs, v1alpha1.TriggeredRun{
+			Name:           run.Name,
+			CounterMeasure: run.Spec.CounterMeasure,
+			Phase:          run.Status.Phase,
+		})
+	}
+	return runs, nil
+}
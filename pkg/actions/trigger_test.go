@@ -0,0 +1,146 @@
+package actions
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestManager_OnTrigger(t *testing.T) {
+	eventsMux := sync.Mutex{}
+	recordedEvents := make([]string, 0)
+
+	bus := eventbus.NewEventBus(1)
+	bus.InjectLogger(testr.New(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Start(ctx)
+
+	mgr, eventsCh := Deploy(t, bus)
+
+	go func() {
+		for s := range eventsCh {
+			eventsMux.Lock()
+			recordedEvents = append(recordedEvents, s)
+			eventsMux.Unlock()
+		}
+	}()
+
+	mgr.ActionRegistry.RegisterAction(v1alpha1.RestartSpec{}, func(spec v1alpha1.Action, c ActionContext, dryRun bool) Action {
+		return &MockAction{}
+	})
+
+	trigger := &v1alpha1.CounterMeasureTrigger{
+		ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "staging-test"},
+		Spec: v1alpha1.CounterMeasureTriggerSpec{
+			CounterMeasure: "selected-events",
+			Data:           map[string]string{"prop1": "value1"},
+		},
+	}
+
+	// the event name of a trigger doesn't need to match the countermeasure
+	event := NewTriggerEvent(trigger, "manual")
+	assert.Equal(t, types.NamespacedName{Namespace: "ns", Name: "staging-test"}, event.Source)
+	assert.Equal(t, "staging-test", event.Data.Get(TriggerDataKey))
+	assert.Equal(t, "value1", event.Data.Get("prop1"))
+
+	target := types.NamespacedName{Namespace: "ns", Name: "selected-events"}
+	bus.Publish(TriggerTopic(target), event)
+	assert.Eventually(t, func() bool {
+		eventsMux.Lock()
+		defer eventsMux.Unlock()
+		return len(recordedEvents) == 1
+	}, time.Second*5, time.Millisecond*100, "expected the action to run")
+	assert.Eventually(t, func() bool {
+		return !mgr.state.IsRunning(manager.ToKey(CreateObjectMeta("selected-events")))
+	}, time.Second*5, time.Millisecond*100, "expected the action to complete")
+
+	// the trigger topic of a countermeasure doesn't match the subscriptions to an event name
+	assert.NotContains(t, TriggerTopic(target), ":")
+
+	// removing the countermeasure unsubscribes from its trigger topic
+	assert.NoError(t, mgr.Remove(target))
+	bus.Publish(TriggerTopic(target), NewTriggerEvent(trigger, "manual"))
+	time.Sleep(200 * time.Millisecond)
+	eventsMux.Lock()
+	assert.Equal(t, 1, len(recordedEvents))
+	eventsMux.Unlock()
+}
+
+func TestTriggeredRuns(t *testing.T) {
+	trigger := &v1alpha1.CounterMeasureTrigger{
+		ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "staging-test"},
+		Status:     v1alpha1.CounterMeasureTriggerStatus{EventKey: "abc123"},
+	}
+
+	newRun := func(name, key string, created time.Time, phase v1alpha1.RunPhase) *v1alpha1.CounterMeasureRun {
+		return &v1alpha1.CounterMeasureRun{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace:         "ns",
+				Name:              name,
+				Labels:            map[string]string{v1alpha1.EventKeyLabel: key},
+				CreationTimestamp: v1.Time{Time: created},
+			},
+			Spec:   v1alpha1.CounterMeasureRunSpec{CounterMeasure: "restart-action"},
+			Status: v1alpha1.CounterMeasureRunStatus{Phase: phase},
+		}
+	}
+
+	now := time.Now().Truncate(time.Second)
+	c := newRunsClient(
+		newRun("second", "abc123", now, v1alpha1.Running),
+		newRun("first", "abc123", now.Add(-time.Minute), v1alpha1.Succeeded),
+		newRun("other", "def456", now, v1alpha1.Succeeded),
+	)
+
+	runs, err := TriggeredRuns(context.TODO(), c, trigger)
+	assert.NoError(t, err)
+	assert.Equal(t, []v1alpha1.TriggeredRun{
+		{Name: "first", CounterMeasure: "restart-action", Phase: v1alpha1.Succeeded},
+		{Name: "second", CounterMeasure: "restart-action", Phase: v1alpha1.Running},
+	}, runs)
+
+	// nothing was injected yet
+	runs, err = TriggeredRuns(context.TODO(), c, &v1alpha1.CounterMeasureTrigger{})
+	assert.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestListeningCounterMeasures(t *testing.T) {
+	newCounterMeasure := func(namespace, name, eventName string) *v1alpha1.CounterMeasure {
+		return &v1alpha1.CounterMeasure{
+			ObjectMeta: v1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       v1alpha1.CounterMeasureSpec{OnEvent: v1alpha1.OnEventSpec{EventName: eventName}},
+		}
+	}
+
+	c := newRunsClient(
+		newCounterMeasure("ns", "restart", "HighLatency"),
+		newCounterMeasure("ns", "scale", "HighLatency"),
+		newCounterMeasure("ns", "purge", "CacheFull"),
+		newCounterMeasure("other-team", "delete", "HighLatency"),
+	)
+
+	listening, err := ListeningCounterMeasures(context.TODO(), c, "ns", "HighLatency")
+	assert.NoError(t, err)
+
+	names := make([]string, 0, len(listening))
+	for _, cm := range listening {
+		names = append(names, cm.Name)
+	}
+	// the countermeasures of other namespaces never receive the event
+	assert.ElementsMatch(t, []string{"restart", "scale"}, names)
+
+	listening, err = ListeningCounterMeasures(context.TODO(), c, "ns", "Unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, listening)
+}
@@ -0,0 +1,327 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OnResolvePollInterval how often to check if the event of a pending revert is no longer active
+const OnResolvePollInterval = 30 * time.Second
+
+// Reversible is implemented by actions that can infer how to undo themselves after being performed.
+type Reversible interface {
+	InferUndo() *v1alpha1.InferredPatch
+}
+
+// Reverter runs the pending reverts of a countermeasure.
+type Reverter interface {
+	Revert(context.Context, *v1alpha1.CounterMeasure) (time.Duration, error)
+}
+
+var _ Reverter = &Manager{}
+
+// newPendingRevert creates a pending revert for an action that has been performed, or
+// nil if the action spec has no undo.
+func newPendingRevert(spec v1alpha1.Action, action Action, event events.Event) *v1alpha1.PendingRevert {
+	undo := spec.Undo
+	if undo == nil {
+		return nil
+	}
+
+	revert := &v1alpha1.PendingRevert{
+		Action:    spec.Name,
+		Event:     ToEventRecord(event),
+		OnResolve: undo.OnResolve,
+	}
+
+	if undo.RevertAfter != nil {
+		revert.RevertAt = &metav1.Time{Time: time.Now().Add(undo.RevertAfter.Duration)}
+	}
+
+	if undo.Delete == nil && undo.Patch == nil {
+		reversible, ok := action.(Reversible)
+		if !ok || reversible.InferUndo() == nil {
+			return nil
+		}
+		revert.InferredPatch = reversible.InferUndo()
+	}
+
+	return revert
+}
+
+// addPendingReverts store the pending reverts on the countermeasure status so they
+// survive operator restarts.
+func addPendingReverts(ctx context.Context, c client.Client, key types.NamespacedName, reverts []v1alpha1.PendingRevert) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		cm, err := getCounterMeasure(ctx, c, key)
+		if err != nil {
+			return err
+		}
+
+		cm.Status.PendingReverts = append(cm.Status.PendingReverts, reverts...)
+		return updateCounterMeasureStatus(ctx, c, cm)
+	})
+}
+
+// Revert runs the pending reverts of the countermeasure that are due, returning how long
+// until the remaining reverts should be checked again, or zero when none remain.
+func (m *Manager) Revert(ctx context.Context, cm *v1alpha1.CounterMeasure) (time.Duration, error) {
+	var (
+		now          = time.Now()
+		remaining    = make([]v1alpha1.PendingRevert, 0)
+		reverted     = make([]v1alpha1.PendingRevert, 0)
+		revertErrors = make([]error, 0)
+		requeueAfter time.Duration
+	)
+
+	for _, pending := range cm.Status.PendingReverts {
+		if !m.isRevertDue(pending, now) {
+			remaining = append(remaining, pending)
+			requeueAfter = minRequeue(requeueAfter, nextRevertCheck(pending, now))
+			continue
+		}
+
+		if err := m.runRevert(ctx, cm, pending); err != nil {
+			m.recorder.Event(cm, "Warning", "RevertError", err.Error())
+			revertErrors = append(revertErrors, err)
+			remaining = append(remaining, pending)
+			requeueAfter = minRequeue(requeueAfter, OnResolvePollInterval)
+			continue
+		}
+
+		reverted = append(reverted, pending)
+		m.recorder.Event(cm, "Normal", "Reverted", fmt.Sprintf("Action '%s' has been reverted", pending.Action))
+	}
+
+	if len(reverted) > 0 {
+		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			latest, err := getCounterMeasure(ctx, m.client, client.ObjectKeyFromObject(cm))
+			if err != nil {
+				return err
+			}
+
+			latest.Status.PendingReverts = removeReverts(latest.Status.PendingReverts, reverted)
+			return updateCounterMeasureStatus(ctx, m.client, latest)
+		})
+
+		if err != nil {
+			revertErrors = append(revertErrors, err)
+		}
+	}
+
+	return requeueAfter, utilerrors.NewAggregate(revertErrors)
+}
+
+// isRevertDue checks if the revert time has passed or the event has resolved.
+func (m *Manager) isRevertDue(pending v1alpha1.PendingRevert, now time.Time) bool {
+	if pending.RevertAt != nil && !pending.RevertAt.Time.After(now) {
+		return true
+	}
+
+	if pending.OnResolve && m.Verifier != nil {
+		active, err := m.Verifier.IsActive(FromEventRecord(pending.Event))
+		if err != nil {
+			managerLog.Error(err, "unable to check if event is still active", "event", pending.Event.Name)
+			return false
+		}
+		return !active
+	}
+
+	return false
+}
+
+// runRevert performs either the undo action or the inferred patch of the pending revert, reverts are
+// subject to the same policies, guards and rate limits as the actions they revert.
+func (m *Manager) runRevert(ctx context.Context, cm *v1alpha1.CounterMeasure, pending v1alpha1.PendingRevert) error {
+	actionClient, restConfig, err := m.actionClient(cm)
+	if err != nil {
+		return err
+	}
+
+	restriction, err := m.namespaceRestriction(ctx, cm)
+	if err != nil {
+		return err
+	}
+
+	actionContext := ActionContext{
+		Client:         m.client,
+		ActionClient:   actionClient,
+		RestConfig:     restConfig,
+		Recorder:       m.recorder,
+		CounterMeasure: *cm,
+		RateLimiter:    m.rateLimiter,
+		Policies:       m.policies,
+		Restriction:    restriction,
+	}
+
+	spec, action, err := m.newRevertAction(actionContext, cm, pending)
+	if err != nil || action == nil {
+		return err
+	}
+
+	event := FromEventRecord(pending.Event)
+	if err := actionContext.checkAction(ctx, spec, action, event); err != nil {
+		return err
+	}
+
+	labels := prometheus.Labels{"namespace": cm.Namespace, "type": action.GetType()}
+	err = retry.OnError(retry.DefaultBackoff, func(err error) bool {
+		return action.SupportsRetry() && !IsRefused(err)
+	}, func() error {
+		return action.Perform(ctx, event)
+	})
+
+	if err != nil {
+		metrics.ActionErrors.With(labels).Add(1)
+		return err
+	}
+
+	metrics.ActionsTaken.With(labels).Add(1)
+	return nil
+}
+
+// newRevertAction creates the action reverting the pending revert along with the spec it's checked
+// against, the action is nil when there is nothing left to revert with.
+func (m *Manager) newRevertAction(actionContext ActionContext, cm *v1alpha1.CounterMeasure,
+	pending v1alpha1.PendingRevert) (*v1alpha1.Action, Action, error) {
+
+	if pending.InferredPatch != nil {
+		spec := &v1alpha1.Action{
+			Name: pending.Action + "-undo",
+			Patch: &v1alpha1.PatchSpec{
+				TargetObjectRef: pending.InferredPatch.TargetObjectRef,
+				PatchType:       types.MergePatchType,
+			},
+		}
+		return spec, NewInferredPatchFromBase(actionContext.newBase(*spec, false), *pending.InferredPatch), nil
+	}
+
+	var undo *v1alpha1.Action
+	for i := range cm.Spec.Actions {
+		if cm.Spec.Actions[i].Name == pending.Action {
+			undo = cm.Spec.Actions[i].UndoAction()
+			break
+		}
+	}
+
+	if undo == nil {
+		// the countermeasure was changed since the action was taken, nothing left to revert with
+		managerLog.Info("undo for action no longer defined, dropping revert", "action", pending.Action)
+		return nil, nil, nil
+	}
+
+	action, err := m.ActionRegistry.create(actionContext, *undo, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return undo, action, nil
+}
+
+// InferredPatch reverts a patch action by applying the merge patch inferred when it was performed
+type InferredPatch struct {
+	BaseAction
+	inferred v1alpha1.InferredPatch
+}
+
+func NewInferredPatchFromBase(base BaseAction, inferred v1alpha1.InferredPatch) *InferredPatch {
+	return &InferredPatch{
+		BaseAction: base,
+		inferred:   inferred,
+	}
+}
+
+func (p *InferredPatch) GetType() string {
+	return "patch"
+}
+
+func (p *InferredPatch) GetTargetObjectName(event events.Event) string {
+	target := p.inferred.TargetObjectRef
+	return p.createObjectName(target.Kind, target.Namespace, target.Name, event)
+}
+
+// Perform applies the inferred merge patch to the object, if it still exists
+func (p *InferredPatch) Perform(ctx context.Context, _ events.Event) error {
+	target := p.inferred.TargetObjectRef
+	gvk, err := target.ToGroupVersionKind()
+	if err != nil {
+		return err
+	}
+
+	if err := p.Restriction.Allows(ctx, target.Namespace); err != nil {
+		return err
+	}
+
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	object.SetNamespace(target.Namespace)
+	object.SetName(target.Name)
+
+	err = p.client.Patch(ctx, object, client.RawPatch(types.MergePatchType, []byte(p.inferred.Patch)))
+	if errors.IsNotFound(err) {
+		// the object is gone so there is nothing left to revert
+		return nil
+	}
+	return err
+}
+
+// nextRevertCheck how long until a pending revert should be checked again
+func nextRevertCheck(pending v1alpha1.PendingRevert, now time.Time) time.Duration {
+	var next time.Duration
+	if pending.RevertAt != nil {
+		next = pending.RevertAt.Time.Sub(now)
+	}
+
+	if pending.OnResolve {
+		next = minRequeue(next, OnResolvePollInterval)
+	}
+
+	return next
+}
+
+// minRequeue the smallest non zero duration
+func minRequeue(a, b time.Duration) time.Duration {
+	if a <= 0 {
+		return b
+	}
+
+	if b > 0 && b < a {
+		return b
+	}
+
+	return a
+}
+
+// removeReverts remove all the reverted entries from the pending reverts
+func removeReverts(pending, reverted []v1alpha1.PendingRevert) []v1alpha1.PendingRevert {
+	remaining := make([]v1alpha1.PendingRevert, 0, len(pending))
+	for _, p := range pending {
+		found := false
+		for _, r := range reverted {
+			if equality.Semantic.DeepEqual(p, r) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			remaining = append(remaining, p)
+		}
+	}
+
+	return remaining
+}
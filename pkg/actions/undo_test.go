@@ -0,0 +1,353 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPatch_InferUndo(t *testing.T) {
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DeploymentName,
+			Namespace: DeploymentNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithRuntimeObjects(deployment).Build()
+
+	spec := v1alpha1.PatchSpec{
+		TargetObjectRef: v1alpha1.ObjectReference{
+			Namespace:  "{{ .Data.namespace }}",
+			Name:       DeploymentName,
+			Kind:       "Deployment",
+			ApiVersion: "apps/v1",
+		},
+		PatchType:    types.MergePatchType,
+		YAMLTemplate: "spec:\n  replicas: 3\n",
+	}
+
+	patch := NewPatchAction(k8sClient, spec)
+	assert.Nil(t, patch.InferUndo())
+
+	data := events.EventData{"namespace": DeploymentNamespace}
+	assert.NoError(t, patch.Perform(context.TODO(), events.Event{Data: &data}))
+	assertReplicas(t, k8sClient, 3)
+
+	inferred := patch.InferUndo()
+	assert.NotNil(t, inferred)
+	assert.Equal(t, DeploymentNamespace, inferred.TargetObjectRef.Namespace)
+	assert.JSONEq(t, `{"spec":{"replicas":1}}`, inferred.Patch)
+
+	undo := NewInferredPatchFromBase(NewBase(k8sClient, v1alpha1.Action{}, false), *inferred)
+	assert.NoError(t, undo.Perform(context.TODO(), events.Event{}))
+	assertReplicas(t, k8sClient, 1)
+}
+
+func TestManager_Revert(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PodName,
+			Namespace: PodNamespace,
+			Labels: map[string]string{
+				"app": "test-app",
+			},
+		},
+	}
+
+	event := events.Event{
+		Name: "event1",
+		Data: &events.EventData{"pod": PodName},
+	}
+
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("undo"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			Actions: []v1alpha1.Action{
+				{
+					Name: "label-pod",
+					Patch: &v1alpha1.PatchSpec{
+						TargetObjectRef: v1alpha1.ObjectReference{
+							Namespace:  PodNamespace,
+							Name:       "{{ .Data.pod }}",
+							Kind:       "Pod",
+							ApiVersion: "v1",
+						},
+						PatchType:    types.MergePatchType,
+						YAMLTemplate: "metadata:\n  labels:\n    isolated: 'true'\n",
+					},
+					Undo: &v1alpha1.UndoSpec{
+						RevertAfter: &metav1.Duration{Duration: time.Minute},
+						Delete: &v1alpha1.DeleteSpec{
+							TargetObjectRef: v1alpha1.ObjectReference{
+								Namespace:  PodNamespace,
+								Name:       "{{ .Data.pod }}",
+								Kind:       "Pod",
+								ApiVersion: "v1",
+							},
+						},
+					},
+				},
+			},
+		},
+		Status: v1alpha1.CounterMeasureStatus{
+			PendingReverts: []v1alpha1.PendingRevert{
+				{
+					Action:   "label-pod",
+					Event:    ToEventRecord(event),
+					RevertAt: &metav1.Time{Time: time.Now().Add(-time.Second)},
+				},
+				{
+					Action:   "label-pod",
+					Event:    ToEventRecord(event),
+					RevertAt: &metav1.Time{Time: time.Now().Add(time.Hour)},
+				},
+			},
+		},
+	}
+
+	s := runtime.NewScheme()
+	clientgoscheme.AddToScheme(s)
+	v1alpha1.AddToScheme(s)
+	// the countermeasure is granted access to the namespace of the pod
+	grant := &v1alpha1.CounterMeasureNamespaceGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: PodNamespace},
+		Spec:       v1alpha1.CounterMeasureNamespaceGrantSpec{FromNamespaces: []string{cm.Namespace}},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(cm.DeepCopy(), pod, grant).Build()
+
+	registry := Registry{}
+	registry.Initialize()
+	mgr := &Manager{
+		client:         k8sClient,
+		recorder:       record.NewFakeRecorder(10),
+		ActionRegistry: registry,
+	}
+
+	// the reconciler reverts using the countermeasure read from the API server
+	deployed := &v1alpha1.CounterMeasure{}
+	key := types.NamespacedName{Namespace: cm.Namespace, Name: cm.Name}
+	assert.NoError(t, k8sClient.Get(context.TODO(), key, deployed))
+
+	requeueAfter, err := mgr.Revert(context.TODO(), deployed)
+	assert.NoError(t, err)
+	assert.True(t, requeueAfter > 59*time.Minute)
+
+	// the undo deleted the pod
+	assertPodExists(t, k8sClient, 0)
+
+	updated := &v1alpha1.CounterMeasure{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), key, updated))
+	assert.Equal(t, 1, len(updated.Status.PendingReverts))
+}
+
+func TestManager_RevertIsChecked(t *testing.T) {
+	event := events.Event{
+		Name: "event1",
+		Data: &events.EventData{"pod": PodName},
+	}
+
+	podRef := v1alpha1.ObjectReference{
+		Namespace:  PodNamespace,
+		Name:       "{{ .Data.pod }}",
+		Kind:       "Pod",
+		ApiVersion: "v1",
+	}
+
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("undo"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			Actions: []v1alpha1.Action{
+				{
+					Name: "label-pod",
+					Patch: &v1alpha1.PatchSpec{
+						TargetObjectRef: podRef,
+						PatchType:       types.MergePatchType,
+						YAMLTemplate:    "metadata:\n  labels:\n    isolated: 'true'\n",
+					},
+					Undo: &v1alpha1.UndoSpec{
+						RevertAfter: &metav1.Duration{Duration: time.Minute},
+						Delete:      &v1alpha1.DeleteSpec{TargetObjectRef: podRef},
+					},
+				},
+			},
+		},
+		Status: v1alpha1.CounterMeasureStatus{
+			PendingReverts: []v1alpha1.PendingRevert{
+				{
+					Action:   "label-pod",
+					Event:    ToEventRecord(event),
+					RevertAt: &metav1.Time{Time: time.Now().Add(-time.Second)},
+				},
+			},
+		},
+	}
+
+	zero := int32(0)
+	tests := []struct {
+		name     string
+		podLabel map[string]string
+		policy   v1alpha1.CounterMeasurePolicySpec
+	}{
+		{
+			name:     "guard",
+			podLabel: map[string]string{"app": "test-app", v1alpha1.ProtectedLabel: "true"},
+		},
+		{
+			name:     "policy",
+			podLabel: map[string]string{"app": "test-app"},
+			policy: v1alpha1.CounterMeasurePolicySpec{
+				Targets: &v1alpha1.TargetPolicy{DeniedKinds: []v1alpha1.KindReference{{Group: "", Kind: "Pod"}}},
+			},
+		},
+		{
+			name:     "rate limit",
+			podLabel: map[string]string{"app": "test-app"},
+			policy: v1alpha1.CounterMeasurePolicySpec{
+				RateLimits: []v1alpha1.ActionRateLimit{
+					{ActionType: "delete", Window: metav1.Duration{Duration: time.Hour}, ClusterLimit: &zero},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: PodName, Namespace: PodNamespace, Labels: tt.podLabel},
+			}
+			policy := &v1alpha1.CounterMeasurePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+				Spec:       tt.policy,
+			}
+			grant := &v1alpha1.CounterMeasureNamespaceGrant{
+				ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: PodNamespace},
+				Spec:       v1alpha1.CounterMeasureNamespaceGrantSpec{FromNamespaces: []string{cm.Namespace}},
+			}
+
+			s := runtime.NewScheme()
+			clientgoscheme.AddToScheme(s)
+			v1alpha1.AddToScheme(s)
+			k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(cm.DeepCopy(), pod, policy, grant).Build()
+
+			registry := Registry{}
+			registry.Initialize()
+			mgr := &Manager{
+				client:         k8sClient,
+				recorder:       record.NewFakeRecorder(10),
+				ActionRegistry: registry,
+				rateLimiter:    NewRateLimiter(k8sClient),
+				policies:       k8sClient,
+			}
+
+			_, err := mgr.Revert(context.TODO(), cm.DeepCopy())
+			assert.Error(t, err)
+
+			// the undo was refused, the pod remains and the revert is still pending
+			assertPodExists(t, k8sClient, 1)
+
+			updated := &v1alpha1.CounterMeasure{}
+			assert.NoError(t, k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cm), updated))
+			assert.Equal(t, 1, len(updated.Status.PendingReverts))
+		})
+	}
+}
+
+func TestInMemoryRunner_RecordsRevertOnSuccess(t *testing.T) {
+	podRef := v1alpha1.ObjectReference{Namespace: PodNamespace, Name: PodName, Kind: "Pod", ApiVersion: "v1"}
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("eager-revert"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			Actions: []v1alpha1.Action{
+				{
+					Name:   "delete-pod",
+					Delete: &v1alpha1.DeleteSpec{TargetObjectRef: podRef},
+					Undo: &v1alpha1.UndoSpec{
+						RevertAfter: &metav1.Duration{Duration: time.Minute},
+						Delete:      &v1alpha1.DeleteSpec{TargetObjectRef: podRef},
+					},
+				},
+				{
+					Name:   "check",
+					Delete: &v1alpha1.DeleteSpec{TargetObjectRef: podRef},
+				},
+			},
+		},
+	}
+
+	k8sClient := newRunsClient(cm.DeepCopy())
+	pending := -1
+	check := &performFunc{perform: func(ctx context.Context) error {
+		updated := &v1alpha1.CounterMeasure{}
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cm), updated); err != nil {
+			return err
+		}
+		pending = len(updated.Status.PendingReverts)
+		return fmt.Errorf("failed")
+	}}
+
+	runner := InMemoryRunner{&MockAction{}, check}
+	err := runner.Run(ActionContext{
+		Client:         k8sClient,
+		Recorder:       record.NewFakeRecorder(10),
+		CounterMeasure: *cm,
+	}, events.Event{Name: "event1", Data: &events.EventData{}})
+	assert.Error(t, err)
+
+	// the revert of the first action was recorded before the second action was taken
+	assert.Equal(t, 1, pending)
+}
+
+// performFunc an action performed by calling the function
+type performFunc struct {
+	MockAction
+	perform func(context.Context) error
+}
+
+func (p *performFunc) Perform(ctx context.Context, _ events.Event) error {
+	return p.perform(ctx)
+}
+
+func TestNewPendingRevert(t *testing.T) {
+	spec := v1alpha1.Action{
+		Name:    "restart",
+		Restart: &v1alpha1.RestartSpec{},
+	}
+	assert.Nil(t, newPendingRevert(spec, &MockAction{}, events.Event{}))
+
+	// a restart can't be inferred
+	spec.Undo = &v1alpha1.UndoSpec{OnResolve: true}
+	assert.Nil(t, newPendingRevert(spec, &MockAction{}, events.Event{}))
+
+	spec.Undo.Delete = &v1alpha1.DeleteSpec{}
+	revert := newPendingRevert(spec, &MockAction{}, events.Event{Name: "event1"})
+	assert.NotNil(t, revert)
+	assert.True(t, revert.OnResolve)
+	assert.Nil(t, revert.RevertAt)
+	assert.Equal(t, "event1", revert.Event.Name)
+}
+
+func assertReplicas(t *testing.T, k8sClient client.Client, expected int32) {
+	deployment := &appsv1.Deployment{}
+	err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: DeploymentNamespace, Name: DeploymentName}, deployment)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, *deployment.Spec.Replicas)
+}
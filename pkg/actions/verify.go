@@ -0,0 +1,91 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/metrics"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// shouldVerify returns true if the countermeasure asks for the triggering event to be verified.
+func (m *Manager) shouldVerify(cm *v1alpha1.CounterMeasure) bool {
+	// nothing was changed during a dry run so there is nothing to verify
+	return cm.Spec.Verify != nil && !cm.Spec.DryRun && m.Verifier != nil
+}
+
+// canVerify checks if the source of the event can tell if the event is still active, so the
+// countermeasure isn't held running for the grace period of a verification bound to fail.
+func (m *Manager) canVerify(event events.Event) bool {
+	checker, ok := m.Verifier.(producer.VerificationChecker)
+	return !ok || checker.CanVerify(event)
+}
+
+// verify waits for the grace period and then asks the event source if the event
+// that triggered the countermeasure is still active.
+func (m *Manager) verify(cm *v1alpha1.CounterMeasure, event events.Event) {
+	time.Sleep(cm.Spec.Verify.GracePeriod.Duration)
+
+	active, err := m.Verifier.IsActive(event)
+	if err != nil {
+		utilruntime.HandleError(err)
+		m.recorder.Event(cm, "Warning", "VerificationError", err.Error())
+		return
+	}
+
+	result := v1alpha1.Resolved
+	if active {
+		result = v1alpha1.Ineffective
+	}
+
+	labels := prometheus.Labels{"namespace": cm.Namespace, "name": cm.Name, "result": string(result)}
+	metrics.Verifications.With(labels).Add(1)
+
+	if result == v1alpha1.Resolved {
+		m.recorder.Event(cm, "Normal", string(result),
+			fmt.Sprintf("Event '%s' is no longer active after the actions were taken", event.Name))
+	} else {
+		m.recorder.Event(cm, "Warning", string(result),
+			fmt.Sprintf("Event '%s' is still active after the actions were taken", event.Name))
+	}
+
+	if err := m.updateVerificationStatus(cm, result); err != nil {
+		managerLog.Error(err, "failed to update countermeasure verification status",
+			"name", cm.Name, "namespace", cm.Namespace)
+	}
+
+	// actions that don't resolve the event count against the circuit breaker
+	if result == v1alpha1.Ineffective {
+		m.recordFailure(context.Background(), cm)
+	} else {
+		m.recordSuccess(context.Background(), cm)
+	}
+}
+
+// updateVerificationStatus record the verification result on the countermeasure status
+func (m *Manager) updateVerificationStatus(cm *v1alpha1.CounterMeasure, result v1alpha1.VerificationType) error {
+	ctx := context.Background()
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest, err := getCounterMeasure(ctx, m.client, client.ObjectKeyFromObject(cm))
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// the countermeasure was deleted while waiting to verify
+				return nil
+			}
+			return err
+		}
+
+		latest.Status.LastVerification = result
+		latest.Status.LastVerificationTime = &metav1.Time{Time: time.Now()}
+		return updateCounterMeasureStatus(ctx, m.client, latest)
+	})
+}
@@ -0,0 +1,141 @@
+package actions
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/actions/state"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type MockVerifier struct {
+	active bool
+}
+
+func (v *MockVerifier) IsActive(events.Event) (bool, error) {
+	return v.active, nil
+}
+
+func TestManager_Verify(t *testing.T) {
+	tests := []struct {
+		name   string
+		active bool
+		want   v1alpha1.VerificationType
+	}{
+		{name: "resolved", active: false, want: v1alpha1.Resolved},
+		{name: "ineffective", active: true, want: v1alpha1.Ineffective},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &v1alpha1.CounterMeasure{
+				ObjectMeta: CreateObjectMeta("verified"),
+				Spec: v1alpha1.CounterMeasureSpec{
+					OnEvent: v1alpha1.OnEventSpec{
+						EventName: "event1",
+					},
+					Verify: &v1alpha1.VerifySpec{
+						GracePeriod: v1.Duration{Duration: time.Millisecond},
+					},
+				},
+			}
+
+			s, _ := v1alpha1.SchemeBuilder.Build()
+			k8sClient := clientfake.NewClientBuilder().
+				WithScheme(s).
+				WithObjects(cm.DeepCopy()).
+				Build()
+
+			recorder := record.NewFakeRecorder(10)
+			mgr := &Manager{
+				client:   k8sClient,
+				recorder: recorder,
+				Verifier: &MockVerifier{active: tt.active},
+			}
+
+			assert.True(t, mgr.shouldVerify(cm))
+			mgr.verify(cm, events.Event{Name: "event1"})
+
+			recorded := <-recorder.Events
+			assert.True(t, strings.Contains(recorded, string(tt.want)), recorded)
+
+			updated := &v1alpha1.CounterMeasure{}
+			err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cm.Namespace, Name: cm.Name}, updated)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, updated.Status.LastVerification)
+			assert.NotNil(t, updated.Status.LastVerificationTime)
+		})
+	}
+}
+
+// UnverifiableVerifier is a verifier whose event sources don't support verification
+type UnverifiableVerifier struct {
+	MockVerifier
+}
+
+func (v *UnverifiableVerifier) CanVerify(events.Event) bool {
+	return false
+}
+
+func TestManager_VerifyUnsupported(t *testing.T) {
+	cm := &v1alpha1.CounterMeasure{
+		ObjectMeta: CreateObjectMeta("unverifiable"),
+		Spec: v1alpha1.CounterMeasureSpec{
+			OnEvent: v1alpha1.OnEventSpec{
+				EventName: "event1",
+			},
+			Verify: &v1alpha1.VerifySpec{
+				GracePeriod: v1.Duration{Duration: time.Hour},
+			},
+		},
+	}
+
+	s, _ := v1alpha1.SchemeBuilder.Build()
+	k8sClient := clientfake.NewClientBuilder().WithScheme(s).WithObjects(cm.DeepCopy()).Build()
+
+	recorder := record.NewFakeRecorder(10)
+	mgr := &Manager{
+		client:   k8sClient,
+		recorder: recorder,
+		state:    state.NewState(),
+		Verifier: &UnverifiableVerifier{},
+	}
+	assert.NoError(t, mgr.state.Add(cm.DeepCopy()))
+
+	event := events.Event{Name: "event1"}
+	key := manager.ToKey(cm.ObjectMeta)
+	mgr.execute(key, cm, event, nil)
+
+	// the countermeasure isn't held running for the grace period
+	assert.False(t, mgr.state.IsRunning(key))
+
+	skipped := false
+	for len(recorder.Events) > 0 {
+		if strings.Contains(<-recorder.Events, "VerificationSkipped") {
+			skipped = true
+		}
+	}
+	assert.True(t, skipped)
+}
+
+func TestManager_ShouldVerify(t *testing.T) {
+	mgr := &Manager{Verifier: &MockVerifier{}}
+
+	cm := &v1alpha1.CounterMeasure{}
+	assert.False(t, mgr.shouldVerify(cm))
+
+	cm.Spec.Verify = &v1alpha1.VerifySpec{}
+	assert.True(t, mgr.shouldVerify(cm))
+
+	cm.Spec.DryRun = true
+	assert.False(t, mgr.shouldVerify(cm))
+}
@@ -0,0 +1,211 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultWaitForPollInterval = 5 * time.Second
+
+type WaitFor struct {
+	BaseAction
+	spec v1alpha1.WaitForSpec
+}
+
+func NewWaitForAction(client client.Client, spec v1alpha1.WaitForSpec) *WaitFor {
+	return NewWaitForFromBase(BaseAction{
+		client: client,
+	}, spec)
+}
+
+func NewWaitForFromBase(base BaseAction, spec v1alpha1.WaitForSpec) *WaitFor {
+	return &WaitFor{
+		BaseAction: base,
+		spec:       spec,
+	}
+}
+
+func (w *WaitFor) GetType() string {
+	return "waitFor"
+}
+
+func (w *WaitFor) GetTargetObjectName(event events.Event) string {
+	if w.spec.Selector != nil {
+		selector := w.spec.Selector
+		return w.createObjectName(selector.Kind, selector.Namespace,
+			metav1.FormatLabelSelector(&selector.LabelSelector), event)
+	}
+
+	target := w.spec.TargetObjectRef
+	return w.createObjectName(target.Kind, target.Namespace, target.Name, event)
+}
+
+// SupportsRetry the timeout is the retry budget for this action, so retrying
+// a timed out wait would only extend it.
+func (w *WaitFor) SupportsRetry() bool {
+	return false
+}
+
+// Perform polls the target objects until the condition holds, returning an error
+// if the timeout expires first.
+func (w *WaitFor) Perform(ctx context.Context, event events.Event) error {
+	if w.DryRun {
+		// none of the previous actions made any changes so there is nothing to wait for
+		return nil
+	}
+
+	namespace := ""
+	if w.spec.Selector != nil {
+		namespace = w.spec.Selector.Namespace
+	} else if w.spec.TargetObjectRef != nil {
+		namespace = w.spec.TargetObjectRef.Namespace
+	}
+
+	if err := w.checkNamespace(ctx, namespace, event); err != nil {
+		return err
+	}
+
+	interval := defaultWaitForPollInterval
+	if w.spec.PollInterval != nil {
+		interval = w.spec.PollInterval.Duration
+	}
+
+	var lastErr error
+	err := wait.PollImmediateWithContext(ctx, interval, w.spec.Timeout.Duration, func(ctx context.Context) (bool, error) {
+		objects, err := w.getObjects(ctx, event)
+		if err != nil {
+			// the object may not exist yet (i.e. being re-created), so keep polling
+			lastErr = err
+			return false, nil
+		}
+
+		if len(objects) == 0 {
+			lastErr = fmt.Errorf("no objects found")
+			return false, nil
+		}
+
+		for _, object := range objects {
+			ok, err := w.matches(object)
+			if err != nil {
+				return false, err
+			}
+
+			if !ok {
+				lastErr = fmt.Errorf("condition not met on '%s/%s'", object.GetNamespace(), object.GetName())
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+
+	if err == wait.ErrWaitTimeout {
+		err = fmt.Errorf("timed out after %s waiting for %s: %v", w.spec.Timeout.Duration,
+			w.GetTargetObjectName(event), lastErr)
+	}
+
+	return err
+}
+
+// getObjects lookup the objects referenced by either the target object ref or the selector
+func (w *WaitFor) getObjects(ctx context.Context, event events.Event) ([]unstructured.Unstructured, error) {
+	if w.spec.Selector != nil {
+		selector := w.spec.Selector
+		gvk, err := (&v1alpha1.ObjectReference{Kind: selector.Kind, ApiVersion: selector.ApiVersion}).ToGroupVersionKind()
+		if err != nil {
+			return nil, err
+		}
+
+		labelSelector, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		err = w.client.List(ctx, list,
+			client.InNamespace(evaluateTemplate(selector.Namespace, event)),
+			client.MatchingLabelsSelector{Selector: labelSelector})
+
+		return list.Items, err
+	}
+
+	target := w.spec.TargetObjectRef
+	gvk, err := target.ToGroupVersionKind()
+	if err != nil {
+		return nil, err
+	}
+
+	object := unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	objectName := ObjectKeyFromTemplate(target.Namespace, target.Name, event)
+	if err = w.client.Get(ctx, objectName, &object); err != nil {
+		return nil, err
+	}
+
+	return []unstructured.Unstructured{object}, nil
+}
+
+// matches check if the object satisfies the condition or the json path
+func (w *WaitFor) matches(object unstructured.Unstructured) (bool, error) {
+	if w.spec.Condition != nil {
+		return hasCondition(object, *w.spec.Condition)
+	}
+
+	return evaluateJSONPath(object, *w.spec.JSONPath)
+}
+
+func hasCondition(object unstructured.Unstructured, match v1alpha1.ConditionMatch) (bool, error) {
+	conditions, found, err := unstructured.NestedSlice(object.Object, "status", "conditions")
+	if err != nil || !found {
+		return false, err
+	}
+
+	status := match.Status
+	if len(status) == 0 {
+		status = metav1.ConditionTrue
+	}
+
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if condition["type"] == match.Type {
+			return condition["status"] == string(status), nil
+		}
+	}
+
+	return false, nil
+}
+
+func evaluateJSONPath(object unstructured.Unstructured, match v1alpha1.JSONPathMatch) (bool, error) {
+	expression := match.Expression
+	if !strings.HasPrefix(expression, "{") {
+		expression = fmt.Sprintf("{%s}", expression)
+	}
+
+	jp := jsonpath.New("waitFor").AllowMissingKeys(true)
+	if err := jp.Parse(expression); err != nil {
+		return false, err
+	}
+
+	var buf bytes.Buffer
+	if err := jp.Execute(&buf, object.Object); err != nil {
+		return false, err
+	}
+
+	return buf.String() == match.Value, nil
+}
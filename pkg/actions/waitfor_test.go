@@ -0,0 +1,157 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/countermeasure/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWaitFor_Condition(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DeploymentName,
+			Namespace: DeploymentNamespace,
+		},
+		Status: appsv1.DeploymentStatus{
+			ReadyReplicas: 2,
+			Conditions: []appsv1.DeploymentCondition{
+				{
+					Type:   appsv1.DeploymentAvailable,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithRuntimeObjects(deployment).Build()
+
+	target := &v1alpha1.ObjectReference{
+		Namespace:  "{{ .Data.namespace }}",
+		Name:       "{{ .Data.deployment }}",
+		Kind:       "Deployment",
+		ApiVersion: "apps/v1",
+	}
+
+	data := events.EventData{
+		"namespace":  DeploymentNamespace,
+		"deployment": DeploymentName,
+	}
+
+	tests := []struct {
+		name    string
+		spec    v1alpha1.WaitForSpec
+		wantErr bool
+	}{
+		{
+			name: "condition met",
+			spec: v1alpha1.WaitForSpec{
+				TargetObjectRef: target,
+				Condition:       &v1alpha1.ConditionMatch{Type: "Available"},
+			},
+		},
+		{
+			name: "condition not met",
+			spec: v1alpha1.WaitForSpec{
+				TargetObjectRef: target,
+				Condition:       &v1alpha1.ConditionMatch{Type: "Available", Status: metav1.ConditionFalse},
+			},
+			wantErr: true,
+		},
+		{
+			name: "json path met",
+			spec: v1alpha1.WaitForSpec{
+				TargetObjectRef: target,
+				JSONPath:        &v1alpha1.JSONPathMatch{Expression: ".status.readyReplicas", Value: "2"},
+			},
+		},
+		{
+			name: "json path not met",
+			spec: v1alpha1.WaitForSpec{
+				TargetObjectRef: target,
+				JSONPath:        &v1alpha1.JSONPathMatch{Expression: "{.status.readyReplicas}", Value: "3"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.spec.Timeout = metav1.Duration{Duration: 100 * time.Millisecond}
+			tt.spec.PollInterval = &metav1.Duration{Duration: 10 * time.Millisecond}
+
+			err := NewWaitForAction(k8sClient, tt.spec).Perform(context.TODO(), events.Event{Data: &data})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWaitFor_Selector(t *testing.T) {
+	objs := []runtime.Object{
+		newWaitForPod("pod-1", corev1.ConditionTrue),
+		newWaitForPod("pod-2", corev1.ConditionFalse),
+	}
+
+	k8sClient := fake.NewClientBuilder().WithRuntimeObjects(objs...).Build()
+
+	spec := v1alpha1.WaitForSpec{
+		Selector: &v1alpha1.ObjectSelector{
+			Namespace:  PodNamespace,
+			Kind:       "Pod",
+			ApiVersion: "v1",
+			LabelSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "test-app"},
+			},
+		},
+		Condition:    &v1alpha1.ConditionMatch{Type: "Ready"},
+		Timeout:      metav1.Duration{Duration: 100 * time.Millisecond},
+		PollInterval: &metav1.Duration{Duration: 10 * time.Millisecond},
+	}
+
+	action := NewWaitForAction(k8sClient, spec)
+	assert.Equal(t, "pod: 'test-namespace/app=test-app'", action.GetTargetObjectName(events.Event{}))
+
+	// one of the pods is not ready so this should timeout
+	err := action.Perform(context.TODO(), events.Event{})
+	assert.Error(t, err)
+
+	// mark all pods ready and check again
+	pod := newWaitForPod("pod-2", corev1.ConditionTrue)
+	pod.ResourceVersion = "999"
+	assert.NoError(t, k8sClient.Update(context.TODO(), pod))
+
+	err = action.Perform(context.TODO(), events.Event{})
+	assert.NoError(t, err)
+}
+
+func newWaitForPod(name string, ready corev1.ConditionStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: PodNamespace,
+			Labels: map[string]string{
+				"app": "test-app",
+			},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{
+					Type:   corev1.PodReady,
+					Status: ready,
+				},
+			},
+		},
+	}
+}
@@ -22,6 +22,12 @@ type Event struct {
 	ActiveTime time.Time `json:"activeTime,omitempty"`
 	// Data is a pointer ref so these events can be added into the workqueue of the Dispatcher
 	Data *EventData `json:"data,omitempty"`
+	// Source is the name of the event source that produced this event
+	Source types.NamespacedName `json:"source,omitempty"`
+	// SourceKind is the kind of the event source that produced this event
+	SourceKind string `json:"sourceKind,omitempty"`
+	// Namespace when set restricts the delivery of the event to the countermeasures in the namespace
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // Key hash the EventData into a key that can be used to de-duplicate events.
@@ -8,6 +8,8 @@ import (
 type ObjectKey struct {
 	types.NamespacedName
 	Generation int64
+	// Kind of the object, objects of different kinds may share a name
+	Kind string
 }
 
 type Manager[T any] interface {
@@ -20,6 +22,13 @@ func (k ObjectKey) GetName() string {
 	return k.Namespace + "/" + k.Name
 }
 
+// ToKindKey convert the ObjectMeta of an object of the kind to an ObjectKey
+func ToKindKey(kind string, meta metav1.ObjectMeta) ObjectKey {
+	key := ToKey(meta)
+	key.Kind = kind
+	return key
+}
+
 // ToKey convert ObjectMeta to an ObjectKey
 func ToKey(meta metav1.ObjectMeta) ObjectKey {
 	return ObjectKey{
@@ -15,8 +15,20 @@ var (
 		Name: "countermeasures_action_errors_total",
 		Help: "Number of total errors encountered while the controller attempted to execute an action",
 	}, []string{"namespace", "type"})
+
+	Verifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "countermeasures_verifications_total",
+		Help: "Number of total verifications of whether a countermeasure resolved the triggering event, by result",
+	}, []string{"namespace", "name", "result"})
+
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "countermeasures_actions_rate_limited_total",
+		Help: "Number of total actions the controller refused to execute because a rate limit was exhausted",
+	}, []string{"namespace", "type"})
 )
 
 func init() {
 	metrics.Registry.MustRegister(ActionsTaken)
+	metrics.Registry.MustRegister(Verifications)
+	metrics.Registry.MustRegister(RateLimited)
 }
@@ -0,0 +1,86 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+)
+
+const (
+	// SupportedVersion the version of the Alertmanager webhook payload that is accepted
+	SupportedVersion = "4"
+
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+
+	// FingerprintKey the EventData key holding the fingerprint of the alert
+	FingerprintKey = "fingerprint"
+	// AnnotationPrefix prefixes the EventData keys holding the annotations of the alert
+	AnnotationPrefix = "annotations."
+)
+
+// Message is the payload posted by an Alertmanager webhook receiver.
+type Message struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []Alert           `json:"alerts"`
+}
+
+// Alert is an alert of the group in the webhook payload.
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// DecodeMessage reads a webhook payload, returning an error if it's not a supported version.
+func DecodeMessage(r io.Reader) (*Message, error) {
+	msg := &Message{}
+	if err := json.NewDecoder(r).Decode(msg); err != nil {
+		return nil, fmt.Errorf("invalid alertmanager payload: %w", err)
+	}
+
+	if msg.Version != SupportedVersion {
+		return nil, fmt.Errorf("unsupported alertmanager payload version '%s', expected '%s'", msg.Version, SupportedVersion)
+	}
+
+	return msg, nil
+}
+
+// IsFiring returns true if the alert is firing
+func (a *Alert) IsFiring() bool {
+	return a.Status == StatusFiring
+}
+
+// ToEvent converts the alert to an Event named after the alert, the labels, annotations and
+// fingerprint of the alert are the data of the event.
+func (a *Alert) ToEvent() events.Event {
+	data := make(events.EventData, len(a.Labels)+len(a.Annotations)+1)
+	for label, value := range a.Labels {
+		data[label] = value
+	}
+	for annotation, value := range a.Annotations {
+		data[AnnotationPrefix+annotation] = value
+	}
+	data[FingerprintKey] = a.Fingerprint
+
+	return events.Event{
+		Name:       a.Labels["alertname"],
+		ActiveTime: a.StartsAt,
+		Data:       &data,
+	}
+}
@@ -0,0 +1,167 @@
+package alertmanager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer/receiver"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var alertmanagerLogger = ctrl.Log.WithName("alertmanager_eventsource")
+
+type AlertmanagerConfig struct {
+	Key           manager.ObjectKey
+	Path          string
+	Receiver      *receiver.Receiver
+	Authenticator receiver.Authenticator
+}
+
+// EventProducer publishes the alerts posted by an Alertmanager webhook receiver to the event bus.
+type EventProducer struct {
+	config   AlertmanagerConfig
+	producer producer.EventProducer
+
+	// firing alerts by fingerprint, used to verify if a published event is still active
+	firingMux sync.RWMutex
+	firing    map[string]struct{}
+}
+
+var _ producer.KeyedEventProducer = &EventProducer{}
+var _ producer.EventVerifier = &EventProducer{}
+var _ http.Handler = &EventProducer{}
+
+// NewEventProducer creation function for a new Alertmanager EventProducer
+func NewEventProducer(cfg AlertmanagerConfig, prd producer.EventProducer) *EventProducer {
+	return &EventProducer{
+		config:   cfg,
+		producer: prd,
+		firing:   make(map[string]struct{}),
+	}
+}
+
+// Start called to start serving the receiver endpoint of this EventProducer.
+func (d *EventProducer) Start(done <-chan struct{}) error {
+	if err := d.config.Receiver.Register(d.source(), d.config.Path, d); err != nil {
+		return err
+	}
+
+	alertmanagerLogger.Info("receiving alertmanager alerts", "path", d.config.Path)
+	<-done
+	alertmanagerLogger.Info("stopped receiving alertmanager alerts", "path", d.config.Path)
+	return nil
+}
+
+// Publish send the event to the bus, retrying on any errors
+func (d *EventProducer) Publish(topic string, event events.Event) error {
+	return retry.OnError(retry.DefaultBackoff, func(err error) bool { return true }, func() error {
+		return d.producer.Publish(topic, event)
+	})
+}
+
+// ServeHTTP authenticates the request, then receives the webhook payload and publishes the firing alerts.
+func (d *EventProducer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := d.config.Authenticator.Authenticate(r.Context(), r.Header, body); err != nil {
+		if !errors.Is(err, receiver.ErrUnauthorized) {
+			alertmanagerLogger.Error(err, "failed to authenticate request", "path", d.config.Path)
+		}
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	msg, err := DecodeMessage(bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := d.receive(msg); err != nil {
+		alertmanagerLogger.Error(err, "failed to publish alerts", "group", msg.GroupKey)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// receive tracks the status of each alert in the message and publishes the firing ones.
+func (d *EventProducer) receive(msg *Message) error {
+	name := d.getName()
+
+	var failed int
+	for _, alert := range msg.Alerts {
+		if !alert.IsFiring() {
+			d.setFiring(alert.Fingerprint, false)
+			continue
+		}
+		d.setFiring(alert.Fingerprint, true)
+
+		event := alert.ToEvent()
+		event.Source = name
+		event.SourceKind = d.config.Key.Kind
+		topic := events.CreateFullyQualifiedTopicName(event.Name, name)
+		if err := d.Publish(topic, event); err != nil {
+			alertmanagerLogger.Error(err, fmt.Sprintf("failed to publish event %v", event.Name))
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to publish %d of %d alerts", failed, len(msg.Alerts))
+	}
+	return nil
+}
+
+func (d *EventProducer) setFiring(fingerprint string, firing bool) {
+	d.firingMux.Lock()
+	defer d.firingMux.Unlock()
+
+	if firing {
+		d.firing[fingerprint] = struct{}{}
+	} else {
+		delete(d.firing, fingerprint)
+	}
+}
+
+// IsActive checks if the alert of the event is still firing, an alert is firing until
+// Alertmanager posts it as resolved.
+func (d *EventProducer) IsActive(event events.Event) (bool, error) {
+	if event.Data == nil {
+		return false, nil
+	}
+
+	d.firingMux.RLock()
+	defer d.firingMux.RUnlock()
+
+	_, ok := d.firing[event.Data.Get(FingerprintKey)]
+	return ok, nil
+}
+
+func (d *EventProducer) Key() manager.ObjectKey {
+	return d.config.Key
+}
+
+// source identifies this event source with the receiver
+func (d *EventProducer) source() receiver.Source {
+	return receiver.Source{Kind: d.config.Key.Kind, NamespacedName: d.config.Key.NamespacedName}
+}
+
+func (d *EventProducer) getName() types.NamespacedName {
+	return d.config.Key.NamespacedName
+}
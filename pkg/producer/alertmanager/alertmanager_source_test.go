@@ -0,0 +1,180 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer/receiver"
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const firingFixture = `{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"PodCrashLooping\"}",
+  "truncatedAlerts": 0,
+  "status": "firing",
+  "receiver": "countermeasures",
+  "groupLabels": {"alertname": "PodCrashLooping"},
+  "commonLabels": {"alertname": "PodCrashLooping", "severity": "critical"},
+  "commonAnnotations": {},
+  "externalURL": "http://alertmanager:9093",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "PodCrashLooping", "severity": "critical", "pod": "app-pod-xyxsl"},
+      "annotations": {"summary": "pod is crash looping"},
+      "startsAt": "2017-01-15T00:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus:9090/graph",
+      "fingerprint": "a1b2c3d4e5f60718"
+    },
+    {
+      "status": "resolved",
+      "labels": {"alertname": "PodCrashLooping", "severity": "critical", "pod": "app-pod-other"},
+      "annotations": {"summary": "pod is crash looping"},
+      "startsAt": "2017-01-14T00:00:00Z",
+      "endsAt": "2017-01-14T01:00:00Z",
+      "generatorURL": "http://prometheus:9090/graph",
+      "fingerprint": "0807060504030201"
+    }
+  ]
+}`
+
+const resolvedFixture = `{
+  "version": "4",
+  "status": "resolved",
+  "alerts": [
+    {
+      "status": "resolved",
+      "labels": {"alertname": "PodCrashLooping", "severity": "critical", "pod": "app-pod-xyxsl"},
+      "startsAt": "2017-01-15T00:00:00Z",
+      "endsAt": "2017-01-15T01:00:00Z",
+      "fingerprint": "a1b2c3d4e5f60718"
+    }
+  ]
+}`
+
+func TestDecodeMessage(t *testing.T) {
+	msg, err := DecodeMessage(strings.NewReader(firingFixture))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(msg.Alerts))
+	assert.True(t, msg.Alerts[0].IsFiring())
+	assert.False(t, msg.Alerts[1].IsFiring())
+
+	event := msg.Alerts[0].ToEvent()
+	assert.Equal(t, "PodCrashLooping", event.Name)
+	assert.Equal(t, time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC), event.ActiveTime)
+	assert.Equal(t, "app-pod-xyxsl", event.Data.Get("pod"))
+	assert.Equal(t, "pod is crash looping", event.Data.Get("annotations.summary"))
+	assert.Equal(t, "a1b2c3d4e5f60718", event.Data.Get(FingerprintKey))
+
+	_, err = DecodeMessage(strings.NewReader(`{"version": "3", "alerts": []}`))
+	assert.Error(t, err)
+
+	_, err = DecodeMessage(strings.NewReader(`not json`))
+	assert.Error(t, err)
+}
+
+func TestEventProducer_Receive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	eventBus := eventbus.NewEventBus(1)
+	eventBus.InjectLogger(testr.New(t))
+	go eventBus.Start(ctx)
+
+	source := types.NamespacedName{Namespace: "ns1", Name: "am1"}
+	eventCh, err := eventBus.Subscribe(events.CreateFullyQualifiedTopicName("PodCrashLooping", source))
+	assert.NoError(t, err)
+
+	secrets := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "am-webhook"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}).Build()
+
+	authenticator, err := receiver.NewAuthenticator(secrets, "ns1", v1alpha1.ReceiverAuth{
+		BearerToken: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "am-webhook"},
+			Key:                  "token",
+		},
+	})
+	assert.NoError(t, err)
+
+	rcv := receiver.NewReceiver("")
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	eventsource := NewEventProducer(AlertmanagerConfig{
+		Key:           manager.ToKey(metav1.ObjectMeta{Namespace: source.Namespace, Name: source.Name, Generation: 1}),
+		Path:          "/alertmanager/ns1/am1",
+		Receiver:      rcv,
+		Authenticator: authenticator,
+	}, eventBus)
+	assert.Equal(t, "ns1/am1", eventsource.Key().GetName())
+
+	go eventsource.Start(ctx.Done())
+
+	token := "wrong"
+	post := func(path, body string) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(body))
+		if !assert.NoError(t, err) {
+			return 0
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return 0
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Eventually(t, func() bool {
+		return post("/alertmanager/ns1/am1", firingFixture) == http.StatusUnauthorized
+	}, 5*time.Second, 10*time.Millisecond)
+
+	token = "s3cr3t"
+	assert.Equal(t, http.StatusOK, post("/alertmanager/ns1/am1", firingFixture))
+
+	var received events.Event
+	select {
+	case received = <-eventCh.OnEvent():
+		assert.Equal(t, "PodCrashLooping", received.Name)
+		assert.Equal(t, source, received.Source)
+		assert.Equal(t, "app-pod-xyxsl", received.Data.Get("pod"))
+	case <-time.After(time.Second * 5):
+		t.Fatal("event never arrived")
+	}
+
+	active, err := eventsource.IsActive(received)
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	assert.Equal(t, http.StatusOK, post("/alertmanager/ns1/am1", resolvedFixture))
+	active, err = eventsource.IsActive(received)
+	assert.NoError(t, err)
+	assert.False(t, active)
+
+	assert.Equal(t, http.StatusBadRequest, post("/alertmanager/ns1/am1", `{"version": "3"}`))
+	assert.Equal(t, http.StatusNotFound, post("/alertmanager/ns1/other", firingFixture))
+
+	// another event source can't take the path
+	assert.Error(t, rcv.Register(receiver.Source{NamespacedName: types.NamespacedName{Namespace: "ns2", Name: "am2"}}, "/alertmanager/ns1/am1", eventsource))
+
+	rcv.Unregister(receiver.Source{NamespacedName: source})
+	assert.Equal(t, http.StatusNotFound, post("/alertmanager/ns1/am1", firingFixture))
+}
@@ -0,0 +1,244 @@
+package httpevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	// cloudEventsContentType the content type of a CloudEvent in structured mode
+	cloudEventsContentType = "application/cloudevents+json"
+	// cloudEventsHeaderPrefix prefixes the headers holding the attributes of a CloudEvent in binary mode
+	cloudEventsHeaderPrefix = "Ce-"
+)
+
+var ErrUnsupportedContentType = errors.New("unsupported content type, expected JSON or a CloudEvent")
+
+// Payload is the document the mapping is evaluated against
+type Payload struct {
+	Document   map[string]interface{}
+	CloudEvent bool
+}
+
+// DecodePayload reads a CloudEvent in structured or binary mode, or a plain JSON object. The
+// document of a CloudEvent is always its structured mode envelope.
+func DecodePayload(header http.Header, body []byte) (*Payload, error) {
+	mediaType := ""
+	if contentType := header.Get("Content-Type"); len(contentType) > 0 {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid content type: %w", err)
+		}
+		mediaType = parsed
+	}
+
+	if mediaType == cloudEventsContentType {
+		doc, err := decodeObject(body)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateCloudEvent(doc); err != nil {
+			return nil, err
+		}
+		return &Payload{Document: doc, CloudEvent: true}, nil
+	}
+
+	if len(header.Get(cloudEventsHeaderPrefix+"Specversion")) > 0 {
+		return decodeBinary(header, mediaType, body)
+	}
+
+	if !isJSON(mediaType) {
+		return nil, ErrUnsupportedContentType
+	}
+
+	doc, err := decodeObject(body)
+	if err != nil {
+		return nil, err
+	}
+	return &Payload{Document: doc}, nil
+}
+
+// decodeBinary builds the envelope of a CloudEvent in binary mode from its headers and body
+func decodeBinary(header http.Header, mediaType string, body []byte) (*Payload, error) {
+	doc := make(map[string]interface{})
+	for name, values := range header {
+		if strings.HasPrefix(name, cloudEventsHeaderPrefix) && len(values) > 0 {
+			doc[strings.ToLower(strings.TrimPrefix(name, cloudEventsHeaderPrefix))] = values[0]
+		}
+	}
+
+	if len(mediaType) > 0 {
+		doc["datacontenttype"] = header.Get("Content-Type")
+	}
+
+	if len(body) > 0 {
+		if isJSON(mediaType) {
+			var data interface{}
+			if err := decodeJSON(body, &data); err != nil {
+				return nil, err
+			}
+			doc["data"] = data
+		} else {
+			doc["data"] = string(body)
+		}
+	}
+
+	if err := validateCloudEvent(doc); err != nil {
+		return nil, err
+	}
+	return &Payload{Document: doc, CloudEvent: true}, nil
+}
+
+func validateCloudEvent(doc map[string]interface{}) error {
+	for _, attr := range []string{"specversion", "type", "source", "id"} {
+		if _, ok := doc[attr]; !ok {
+			return fmt.Errorf("invalid CloudEvent, missing the required attribute '%s'", attr)
+		}
+	}
+	return nil
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func decodeObject(body []byte) (map[string]interface{}, error) {
+	doc := make(map[string]interface{})
+	if err := decodeJSON(body, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// decodeJSON decodes numbers as json.Number so they keep their formatting in the event data
+func decodeJSON(body []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON payload: %w", err)
+	}
+	return nil
+}
+
+// Mapping maps a payload to an event
+type Mapping struct {
+	name *jsonpath.JSONPath
+	data map[string]*jsonpath.JSONPath
+}
+
+// NewMapping parses the JSONPath templates of the mapping
+func NewMapping(spec v1alpha1.EventMapping) (*Mapping, error) {
+	mapping := &Mapping{
+		data: make(map[string]*jsonpath.JSONPath, len(spec.Data)),
+	}
+
+	if len(spec.Name) > 0 {
+		jp, err := parseTemplate("name", spec.Name)
+		if err != nil {
+			return nil, err
+		}
+		mapping.name = jp
+	}
+
+	for key, tmpl := range spec.Data {
+		jp, err := parseTemplate(key, tmpl)
+		if err != nil {
+			return nil, err
+		}
+		mapping.data[key] = jp
+	}
+
+	return mapping, nil
+}
+
+func parseTemplate(name, tmpl string) (*jsonpath.JSONPath, error) {
+	jp := jsonpath.New(name).AllowMissingKeys(true)
+	if err := jp.Parse(tmpl); err != nil {
+		return nil, fmt.Errorf("invalid mapping for '%s': %w", name, err)
+	}
+	return jp, nil
+}
+
+// ToEvent maps the payload to an event
+func (m *Mapping) ToEvent(payload *Payload) (events.Event, error) {
+	name, err := m.eventName(payload)
+	if err != nil {
+		return events.Event{}, err
+	}
+
+	data := make(events.EventData)
+	if len(m.data) > 0 {
+		for key, jp := range m.data {
+			value, err := execute(jp, payload.Document)
+			if err != nil {
+				return events.Event{}, err
+			}
+			data[key] = value
+		}
+	} else {
+		fields := payload.Document
+		if payload.CloudEvent {
+			fields, _ = payload.Document["data"].(map[string]interface{})
+		}
+		for key, value := range fields {
+			switch v := value.(type) {
+			case string, json.Number, bool:
+				data[key] = fmt.Sprint(v)
+			}
+		}
+	}
+
+	return events.Event{
+		Name:       name,
+		ActiveTime: activeTime(payload),
+		Data:       &data,
+	}, nil
+}
+
+func (m *Mapping) eventName(payload *Payload) (string, error) {
+	var name string
+	if m.name != nil {
+		value, err := execute(m.name, payload.Document)
+		if err != nil {
+			return "", err
+		}
+		name = value
+	} else if payload.CloudEvent {
+		name = fmt.Sprint(payload.Document["type"])
+	}
+
+	if len(name) == 0 {
+		return "", errors.New("the payload is mapped to an empty event name")
+	}
+	return name, nil
+}
+
+func execute(jp *jsonpath.JSONPath, doc interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := jp.Execute(&buf, doc); err != nil {
+		return "", fmt.Errorf("failed to map the payload: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// activeTime the time attribute of a CloudEvent, otherwise when it was received
+func activeTime(payload *Payload) time.Time {
+	if payload.CloudEvent {
+		if value, ok := payload.Document["time"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Now()
+}
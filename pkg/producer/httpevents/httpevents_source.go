@@ -0,0 +1,124 @@
+package httpevents
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer/receiver"
+)
+
+var httpEventsLogger = ctrl.Log.WithName("httpevents_eventsource")
+
+type HTTPEventsConfig struct {
+	Key           manager.ObjectKey
+	Path          string
+	Receiver      *receiver.Receiver
+	Authenticator receiver.Authenticator
+	Mapping       *Mapping
+}
+
+// EventProducer publishes the events posted to its endpoint to the event bus.
+type EventProducer struct {
+	config   HTTPEventsConfig
+	producer producer.EventProducer
+}
+
+var _ producer.KeyedEventProducer = &EventProducer{}
+var _ http.Handler = &EventProducer{}
+
+// NewEventProducer creation function for a new HTTPEvents EventProducer
+func NewEventProducer(cfg HTTPEventsConfig, prd producer.EventProducer) *EventProducer {
+	return &EventProducer{
+		config:   cfg,
+		producer: prd,
+	}
+}
+
+// Start called to start serving the receiver endpoint of this EventProducer.
+func (d *EventProducer) Start(done <-chan struct{}) error {
+	if err := d.config.Receiver.Register(d.source(), d.config.Path, d); err != nil {
+		return err
+	}
+
+	httpEventsLogger.Info("receiving http events", "path", d.config.Path)
+	<-done
+	httpEventsLogger.Info("stopped receiving http events", "path", d.config.Path)
+	return nil
+}
+
+// Publish send the event to the bus, retrying on any errors
+func (d *EventProducer) Publish(topic string, event events.Event) error {
+	return retry.OnError(retry.DefaultBackoff, func(err error) bool { return true }, func() error {
+		return d.producer.Publish(topic, event)
+	})
+}
+
+// ServeHTTP authenticates the request and publishes the event its payload maps to.
+func (d *EventProducer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := d.config.Authenticator.Authenticate(r.Context(), r.Header, body); err != nil {
+		if !errors.Is(err, receiver.ErrUnauthorized) {
+			httpEventsLogger.Error(err, "failed to authenticate request", "path", d.config.Path)
+		}
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	payload, err := DecodePayload(r.Header, body)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrUnsupportedContentType) {
+			status = http.StatusUnsupportedMediaType
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	event, err := d.config.Mapping.ToEvent(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	name := d.getName()
+	event.Source = name
+	event.SourceKind = d.config.Key.Kind
+	// the credentials of the event source belong to its namespace, the events posted with them
+	// must not run the countermeasures of any other namespace.
+	event.Namespace = name.Namespace
+	topic := events.CreateFullyQualifiedTopicName(event.Name, name)
+	if err := d.Publish(topic, event); err != nil {
+		httpEventsLogger.Error(err, fmt.Sprintf("failed to publish event %v", event.Name))
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (d *EventProducer) Key() manager.ObjectKey {
+	return d.config.Key
+}
+
+// source identifies this event source with the receiver
+func (d *EventProducer) source() receiver.Source {
+	return receiver.Source{Kind: d.config.Key.Kind, NamespacedName: d.config.Key.NamespacedName}
+}
+
+func (d *EventProducer) getName() types.NamespacedName {
+	return d.config.Key.NamespacedName
+}
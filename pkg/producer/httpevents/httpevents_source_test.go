@@ -0,0 +1,184 @@
+package httpevents
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer/receiver"
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const structuredFixture = `{
+  "specversion": "1.0",
+  "type": "com.example.deploy.failed",
+  "source": "/ci/pipelines/42",
+  "id": "A234-1234-1234",
+  "time": "2017-01-15T00:00:00Z",
+  "datacontenttype": "application/json",
+  "data": {"deployment": "checkout", "namespace": "shop", "attempt": 3, "labels": {"team": "a"}}
+}`
+
+func TestDecodePayload(t *testing.T) {
+	mapping, err := NewMapping(v1alpha1.EventMapping{})
+	assert.NoError(t, err)
+
+	// structured mode
+	header := http.Header{"Content-Type": {"application/cloudevents+json; charset=utf-8"}}
+	payload, err := DecodePayload(header, []byte(structuredFixture))
+	assert.NoError(t, err)
+	assert.True(t, payload.CloudEvent)
+
+	event, err := mapping.ToEvent(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, "com.example.deploy.failed", event.Name)
+	assert.Equal(t, time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC), event.ActiveTime)
+	assert.Equal(t, events.EventData{"deployment": "checkout", "namespace": "shop", "attempt": "3"}, *event.Data)
+
+	// binary mode
+	header = http.Header{
+		"Content-Type":   {"application/json"},
+		"Ce-Specversion": {"1.0"},
+		"Ce-Type":        {"com.example.deploy.failed"},
+		"Ce-Source":      {"/ci/pipelines/42"},
+		"Ce-Id":          {"A234-1234-1234"},
+	}
+	payload, err = DecodePayload(header, []byte(`{"deployment": "checkout", "namespace": "shop"}`))
+	assert.NoError(t, err)
+	assert.True(t, payload.CloudEvent)
+
+	event, err = mapping.ToEvent(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, "com.example.deploy.failed", event.Name)
+	assert.Equal(t, "checkout", event.Data.Get("deployment"))
+
+	// a CloudEvent missing a required attribute
+	header.Del("Ce-Id")
+	_, err = DecodePayload(header, []byte(`{}`))
+	assert.Error(t, err)
+
+	// plain JSON needs a name mapping
+	payload, err = DecodePayload(http.Header{"Content-Type": {"application/json"}}, []byte(`{"check": "checkout-latency"}`))
+	assert.NoError(t, err)
+	assert.False(t, payload.CloudEvent)
+	_, err = mapping.ToEvent(payload)
+	assert.Error(t, err)
+
+	_, err = DecodePayload(http.Header{"Content-Type": {"text/plain"}}, []byte(`hello`))
+	assert.ErrorIs(t, err, ErrUnsupportedContentType)
+}
+
+func TestMapping_ToEvent(t *testing.T) {
+	mapping, err := NewMapping(v1alpha1.EventMapping{
+		Name: "SyntheticCheckFailed-{.check}",
+		Data: map[string]string{
+			"deployment": "{.target.deployment}",
+			"team":       "{.target.labels.team}",
+			"missing":    "{.nothing}",
+		},
+	})
+	assert.NoError(t, err)
+
+	payload, err := DecodePayload(http.Header{}, []byte(`{"check": "latency", "target": {"deployment": "checkout", "labels": {"team": "a"}}}`))
+	assert.NoError(t, err)
+
+	event, err := mapping.ToEvent(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, "SyntheticCheckFailed-latency", event.Name)
+	assert.Equal(t, events.EventData{"deployment": "checkout", "team": "a", "missing": ""}, *event.Data)
+
+	_, err = NewMapping(v1alpha1.EventMapping{Name: "{.unterminated"})
+	assert.Error(t, err)
+}
+
+func TestEventProducer_Receive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	eventBus := eventbus.NewEventBus(1)
+	eventBus.InjectLogger(testr.New(t))
+	go eventBus.Start(ctx)
+
+	source := types.NamespacedName{Namespace: "ns1", Name: "ci"}
+	eventCh, err := eventBus.Subscribe(events.CreateFullyQualifiedTopicName("com.example.deploy.failed", source))
+	assert.NoError(t, err)
+
+	secrets := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "ci-webhook"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}).Build()
+
+	rcv := receiver.NewReceiver("")
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	post := func(path string, header http.Header, body string) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(body))
+		if !assert.NoError(t, err) {
+			return 0
+		}
+		req.Header = header
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return 0
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	mapping, err := NewMapping(v1alpha1.EventMapping{})
+	assert.NoError(t, err)
+
+	// bearer token
+	bearer, err := receiver.NewAuthenticator(secrets, "ns1", v1alpha1.ReceiverAuth{
+		BearerToken: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "ci-webhook"},
+			Key:                  "token",
+		},
+	})
+	assert.NoError(t, err)
+
+	eventsource := NewEventProducer(HTTPEventsConfig{
+		Key:           manager.ToKey(metav1.ObjectMeta{Namespace: source.Namespace, Name: source.Name, Generation: 1}),
+		Path:          "/events/ns1/ci",
+		Receiver:      rcv,
+		Authenticator: bearer,
+		Mapping:       mapping,
+	}, eventBus)
+	go eventsource.Start(ctx.Done())
+
+	structured := http.Header{"Content-Type": {"application/cloudevents+json"}}
+	assert.Eventually(t, func() bool {
+		return post("/events/ns1/ci", structured, structuredFixture) == http.StatusUnauthorized
+	}, 5*time.Second, 10*time.Millisecond)
+
+	structured.Set("Authorization", "Bearer wrong")
+	assert.Equal(t, http.StatusUnauthorized, post("/events/ns1/ci", structured, structuredFixture))
+
+	structured.Set("Authorization", "Bearer s3cr3t")
+	assert.Equal(t, http.StatusAccepted, post("/events/ns1/ci", structured, structuredFixture))
+
+	select {
+	case event := <-eventCh.OnEvent():
+		assert.Equal(t, "com.example.deploy.failed", event.Name)
+		assert.Equal(t, source, event.Source)
+		assert.Equal(t, "ns1", event.Namespace)
+		assert.Equal(t, "checkout", event.Data.Get("deployment"))
+	case <-time.After(time.Second * 5):
+		t.Fatal("event never arrived")
+	}
+
+	assert.Equal(t, http.StatusBadRequest, post("/events/ns1/ci", structured, `{"specversion": "1.0"}`))
+}
@@ -0,0 +1,107 @@
+package k8sevents
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Filter matches the core/v1 Events that are published, an empty filter matches every Event.
+type Filter struct {
+	Reasons       sets.String
+	InvolvedKinds sets.String
+	Type          string
+	Message       *regexp.Regexp
+	// NamespaceSelector selects the namespaces of the Events, nil when only the
+	// Events of a single namespace are watched.
+	NamespaceSelector labels.Selector
+}
+
+// NewFilter creates the filter of the KubernetesEvents event source.
+func NewFilter(spec v1alpha1.KubernetesEventsSpec) (*Filter, error) {
+	filter := &Filter{
+		Reasons:       sets.NewString(spec.Reasons...),
+		InvolvedKinds: sets.NewString(spec.InvolvedKinds...),
+		Type:          spec.Type,
+	}
+
+	if len(spec.MessageRegex) > 0 {
+		re, err := regexp.Compile(spec.MessageRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid messageRegex: %w", err)
+		}
+		filter.Message = re
+	}
+
+	if spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+		filter.NamespaceSelector = selector
+	}
+
+	return filter, nil
+}
+
+// Matches checks the Event against every filter except the namespace selector.
+func (f *Filter) Matches(e *corev1.Event) bool {
+	if f.Reasons.Len() > 0 && !f.Reasons.Has(e.Reason) {
+		return false
+	}
+
+	if f.InvolvedKinds.Len() > 0 && !f.InvolvedKinds.Has(e.InvolvedObject.Kind) {
+		return false
+	}
+
+	if len(f.Type) > 0 && f.Type != e.Type {
+		return false
+	}
+
+	if f.Message != nil && !f.Message.MatchString(e.Message) {
+		return false
+	}
+
+	return true
+}
+
+// lastSeen the last time the Event occurred, whichever of the timestamps the reporter set.
+func lastSeen(e *corev1.Event) time.Time {
+	if e.Series != nil && !e.Series.LastObservedTime.IsZero() {
+		return e.Series.LastObservedTime.Time
+	}
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+// ToEvent converts the core/v1 Event to an Event named after its reason, the involved object
+// of the Event is the data of the event.
+func ToEvent(e *corev1.Event) events.Event {
+	data := events.EventData{
+		"name":      e.InvolvedObject.Name,
+		"namespace": e.InvolvedObject.Namespace,
+		"kind":      e.InvolvedObject.Kind,
+		"uid":       string(e.InvolvedObject.UID),
+		"reason":    e.Reason,
+		"type":      e.Type,
+		"message":   e.Message,
+	}
+
+	return events.Event{
+		Name:       e.Reason,
+		ActiveTime: lastSeen(e),
+		Data:       &data,
+	}
+}
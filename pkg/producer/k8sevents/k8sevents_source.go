@@ -0,0 +1,143 @@
+package k8sevents
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer"
+)
+
+var k8sEventsLogger = ctrl.Log.WithName("kubernetes_eventsource")
+
+type KubernetesEventsConfig struct {
+	Key    manager.ObjectKey
+	Filter *Filter
+	// Clientset watches the core/v1 Events
+	Clientset kubernetes.Interface
+	// Namespaces reads the labels of the namespaces matched against the namespace selector
+	Namespaces client.Reader
+	// ResyncPeriod of the informer, zero disables resyncs
+	ResyncPeriod time.Duration
+}
+
+// EventProducer publishes the core/v1 Events matching the filter to the event bus.
+type EventProducer struct {
+	config   KubernetesEventsConfig
+	producer producer.EventProducer
+
+	// Events that occurred before the producer started aren't published
+	started time.Time
+}
+
+var _ producer.KeyedEventProducer = &EventProducer{}
+
+// NewEventProducer creation function for a new KubernetesEvents EventProducer
+func NewEventProducer(cfg KubernetesEventsConfig, prd producer.EventProducer) *EventProducer {
+	return &EventProducer{
+		config:   cfg,
+		producer: prd,
+	}
+}
+
+// Start called to start watching the Events, the watch stops when the done channel closes.
+func (d *EventProducer) Start(done <-chan struct{}) error {
+	d.started = time.Now()
+
+	informer := cache.NewSharedIndexInformer(d.listWatch(), &corev1.Event{}, d.config.ResyncPeriod, cache.Indexers{})
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			d.onEvent(nil, obj)
+		},
+		UpdateFunc: d.onEvent,
+	})
+
+	k8sEventsLogger.Info("starting kubernetes events watch", "name", d.getName())
+	informer.Run(done)
+	k8sEventsLogger.Info("stopping kubernetes events watch", "name", d.getName())
+	return nil
+}
+
+// Publish send the event to the bus, retrying on any errors
+func (d *EventProducer) Publish(topic string, event events.Event) error {
+	return retry.OnError(retry.DefaultBackoff, func(err error) bool { return true }, func() error {
+		return d.producer.Publish(topic, event)
+	})
+}
+
+// listWatch watches the Events of the namespace of the event source, or of all namespaces
+// when the namespaces are chosen by a selector.
+func (d *EventProducer) listWatch() *cache.ListWatch {
+	namespace := producer.WatchNamespace(d.getName().Namespace, d.config.Filter.NamespaceSelector)
+	events := d.config.Clientset.CoreV1().Events(namespace)
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return events.List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return events.Watch(context.Background(), options)
+		},
+	}
+}
+
+// onEvent publishes a new occurrence of an Event if it matches the filters, the old object
+// is nil when the Event was added.
+func (d *EventProducer) onEvent(oldObj, newObj interface{}) {
+	e, ok := newObj.(*corev1.Event)
+	if !ok {
+		return
+	}
+
+	seen := lastSeen(e)
+	if seen.Before(d.started) {
+		return
+	}
+
+	// updates that aren't a new occurrence of the Event, like resyncs, were already handled
+	if old, ok := oldObj.(*corev1.Event); ok && !seen.After(lastSeen(old)) {
+		return
+	}
+
+	if !d.config.Filter.Matches(e) {
+		return
+	}
+
+	selected, err := producer.SelectsNamespace(context.Background(), d.config.Namespaces, d.config.Filter.NamespaceSelector, e.Namespace)
+	if err != nil {
+		k8sEventsLogger.Error(err, "failed to read namespace", "namespace", e.Namespace)
+		return
+	}
+	if !selected {
+		return
+	}
+
+	name := d.getName()
+	event := ToEvent(e)
+	event.Source = name
+	event.SourceKind = d.config.Key.Kind
+	topic := events.CreateFullyQualifiedTopicName(event.Name, name)
+	if err := d.Publish(topic, event); err != nil {
+		k8sEventsLogger.Error(err, fmt.Sprintf("failed to publish event %v", event.Name))
+	}
+}
+
+func (d *EventProducer) Key() manager.ObjectKey {
+	return d.config.Key
+}
+
+func (d *EventProducer) getName() types.NamespacedName {
+	return d.config.Key.NamespacedName
+}
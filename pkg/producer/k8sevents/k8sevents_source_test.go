@@ -0,0 +1,139 @@
+package k8sevents
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newEvent(namespace, name, reason, eventType, message string, seen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: namespace,
+			Name:      "app-pod-xyxsl",
+			UID:       types.UID("2d5b4f8e"),
+		},
+		Reason:        reason,
+		Type:          eventType,
+		Message:       message,
+		LastTimestamp: metav1.NewTime(seen),
+	}
+}
+
+func TestFilter_Matches(t *testing.T) {
+	filter, err := NewFilter(v1alpha1.KubernetesEventsSpec{
+		Reasons:       []string{"BackOff", "FailedMount"},
+		InvolvedKinds: []string{"Pod"},
+		Type:          corev1.EventTypeWarning,
+		MessageRegex:  "^Back-off restarting",
+	})
+	assert.NoError(t, err)
+
+	now := time.Now()
+	assert.True(t, filter.Matches(newEvent("ns1", "e1", "BackOff", corev1.EventTypeWarning, "Back-off restarting failed container", now)))
+	assert.False(t, filter.Matches(newEvent("ns1", "e1", "Pulled", corev1.EventTypeWarning, "Back-off restarting failed container", now)))
+	assert.False(t, filter.Matches(newEvent("ns1", "e1", "BackOff", corev1.EventTypeNormal, "Back-off restarting failed container", now)))
+	assert.False(t, filter.Matches(newEvent("ns1", "e1", "BackOff", corev1.EventTypeWarning, "Back-off pulling image", now)))
+
+	e := newEvent("ns1", "e1", "BackOff", corev1.EventTypeWarning, "Back-off restarting failed container", now)
+	e.InvolvedObject.Kind = "Node"
+	assert.False(t, filter.Matches(e))
+
+	empty, err := NewFilter(v1alpha1.KubernetesEventsSpec{})
+	assert.NoError(t, err)
+	assert.True(t, empty.Matches(e))
+
+	_, err = NewFilter(v1alpha1.KubernetesEventsSpec{MessageRegex: "("})
+	assert.Error(t, err)
+}
+
+func TestToEvent(t *testing.T) {
+	seen := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	event := ToEvent(newEvent("ns1", "e1", "BackOff", corev1.EventTypeWarning, "Back-off restarting failed container", seen))
+
+	assert.Equal(t, "BackOff", event.Name)
+	assert.Equal(t, seen, event.ActiveTime)
+	assert.Equal(t, "app-pod-xyxsl", event.Data.Get("name"))
+	assert.Equal(t, "ns1", event.Data.Get("namespace"))
+	assert.Equal(t, "Pod", event.Data.Get("kind"))
+	assert.Equal(t, "2d5b4f8e", event.Data.Get("uid"))
+}
+
+func TestEventProducer_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	eventBus := eventbus.NewEventBus(1)
+	eventBus.InjectLogger(testr.New(t))
+	go eventBus.Start(ctx)
+
+	source := types.NamespacedName{Namespace: "ns1", Name: "kes1"}
+	eventCh, err := eventBus.Subscribe(events.CreateFullyQualifiedTopicName("BackOff", source))
+	assert.NoError(t, err)
+
+	// the events that occurred before the producer started aren't published
+	clientset := fake.NewSimpleClientset(newEvent("ns1", "old", "BackOff", corev1.EventTypeWarning, "old", time.Now().Add(-time.Hour)))
+
+	filter, err := NewFilter(v1alpha1.KubernetesEventsSpec{
+		Reasons: []string{"BackOff"},
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"team": "a"},
+		},
+	})
+	assert.NoError(t, err)
+
+	namespaces := clientfake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Labels: map[string]string{"team": "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2", Labels: map[string]string{"team": "b"}}},
+	).Build()
+
+	eventsource := NewEventProducer(KubernetesEventsConfig{
+		Key:        manager.ToKey(metav1.ObjectMeta{Namespace: source.Namespace, Name: source.Name, Generation: 1}),
+		Filter:     filter,
+		Clientset:  clientset,
+		Namespaces: namespaces,
+	}, eventBus)
+	assert.Equal(t, "ns1/kes1", eventsource.Key().GetName())
+
+	go eventsource.Start(ctx.Done())
+
+	// occurring after the producer started, whether they're listed or watched
+	now := time.Now().Add(time.Minute)
+	for _, e := range []*corev1.Event{
+		newEvent("ns2", "unselected", "BackOff", corev1.EventTypeWarning, "unselected", now),
+		newEvent("ns1", "filtered", "Pulled", corev1.EventTypeNormal, "filtered", now),
+		newEvent("ns1", "matched", "BackOff", corev1.EventTypeWarning, "matched", now),
+	} {
+		_, err := clientset.CoreV1().Events(e.Namespace).Create(ctx, e, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	select {
+	case event := <-eventCh.OnEvent():
+		assert.Equal(t, "BackOff", event.Name)
+		assert.Equal(t, source, event.Source)
+		assert.Equal(t, "matched", event.Data.Get("message"))
+	case <-time.After(time.Second * 5):
+		t.Fatal("event never arrived")
+	}
+
+	select {
+	case event := <-eventCh.OnEvent():
+		t.Fatalf("unexpected event %v", event.Data)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -2,9 +2,11 @@ package producer
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -15,6 +17,8 @@ import (
 )
 
 var _ manager.Manager[KeyedEventProducer] = &Manager{}
+var _ EventVerifier = &Manager{}
+var _ VerificationChecker = &Manager{}
 
 var (
 	managerLog  = ctrl.Log.WithName("producer_manager")
@@ -27,6 +31,8 @@ type Manager struct {
 
 	producersMux sync.RWMutex
 	producers    map[manager.ObjectKey]KeyedEventProducer
+	// stops are closed to stop the producers when they're removed
+	stops map[manager.ObjectKey]chan struct{}
 
 	// This event bus is used by the event producers to publish
 	// events to all the subscribing consumers (i.e. CounterMeasures).
@@ -38,17 +44,33 @@ func NewManager(bus *eventbus.EventBus) *Manager {
 		eventBus:     bus,
 		producersMux: sync.RWMutex{},
 		producers:    make(map[manager.ObjectKey]KeyedEventProducer),
+		stops:        make(map[manager.ObjectKey]chan struct{}),
 	}
 }
 
+// ForKind returns a view of this manager that adds, removes and looks up only the
+// event producers of the kind, so that event sources of different kinds can share a name.
+func (m *Manager) ForKind(kind string) manager.Manager[KeyedEventProducer] {
+	return &kindManager{Manager: m, kind: kind}
+}
+
 // Remove remove an event producer
 func (m *Manager) Remove(name types.NamespacedName) error {
+	return m.remove("", name)
+}
+
+// remove remove the event producers of the kind with the name
+func (m *Manager) remove(kind string, name types.NamespacedName) error {
 	m.producersMux.Lock()
 	defer m.producersMux.Unlock()
 
 	for k := range m.producers {
-		if k.NamespacedName == name {
+		if k.NamespacedName == name && k.Kind == kind {
 			delete(m.producers, k)
+			if stop, ok := m.stops[k]; ok {
+				close(stop)
+				delete(m.stops, k)
+			}
 		}
 	}
 
@@ -57,14 +79,51 @@ func (m *Manager) Remove(name types.NamespacedName) error {
 
 // Exists returns true if this event producer has already been registred
 func (m *Manager) Exists(objectMeta metav1.ObjectMeta) bool {
-	key := manager.ToKey(objectMeta)
+	return m.exists(manager.ToKey(objectMeta))
+}
 
+// exists returns true if an event producer has been registered with the key
+func (m *Manager) exists(key manager.ObjectKey) bool {
 	m.producersMux.RLock()
 	defer m.producersMux.RUnlock()
 	_, ok := m.producers[key]
 	return ok
 }
 
+// IsActive asks the producer that published the event if the event is still active.
+func (m *Manager) IsActive(event events.Event) (bool, error) {
+	m.producersMux.RLock()
+	defer m.producersMux.RUnlock()
+
+	for k, producer := range m.producers {
+		if k.NamespacedName == event.Source && k.Kind == event.SourceKind {
+			verifier, ok := producer.(EventVerifier)
+			if !ok {
+				return false, fmt.Errorf("event source '%s' does not support verification", k.GetName())
+			}
+			return verifier.IsActive(event)
+		}
+	}
+
+	return false, fmt.Errorf("event source '%s' not found", event.Source.String())
+}
+
+// CanVerify checks if the producer that published the event supports verification, the events
+// injected by a CounterMeasureTrigger have no producer so they can't be verified.
+func (m *Manager) CanVerify(event events.Event) bool {
+	m.producersMux.RLock()
+	defer m.producersMux.RUnlock()
+
+	for k, producer := range m.producers {
+		if k.NamespacedName == event.Source && k.Kind == event.SourceKind {
+			_, ok := producer.(EventVerifier)
+			return ok
+		}
+	}
+
+	return false
+}
+
 // Add add an event producer to this manager, if this manager is already started
 // then the producer will also be started, otherwise the producer will start when
 // the manager starts.
@@ -77,17 +136,45 @@ func (m *Manager) Add(producer KeyedEventProducer) error {
 
 	// only start when the manager is started.
 	if m.shutdown != nil {
-		// place the event source on a goroutine so that it won't block this method
-		go func() {
-			retry.OnError(retry.DefaultRetry, ALWAYS_TRUE, func() error {
-				return producer.Start(m.shutdown)
-			})
-		}()
+		m.start(key, producer)
 	}
 
 	return nil
 }
 
+// start runs the producer on a goroutine so that it won't block, until either the
+// producer is removed or the manager shuts down. Must be called holding the lock.
+func (m *Manager) start(key manager.ObjectKey, producer KeyedEventProducer) {
+	if m.stops == nil {
+		m.stops = make(map[manager.ObjectKey]chan struct{})
+	}
+	if stop, ok := m.stops[key]; ok {
+		close(stop)
+	}
+
+	stop := make(chan struct{})
+	m.stops[key] = stop
+
+	shutdown := m.shutdown
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-stop:
+		case <-shutdown:
+		}
+		close(done)
+	}()
+
+	go func() {
+		err := retry.OnError(retry.DefaultRetry, ALWAYS_TRUE, func() error {
+			return producer.Start(done)
+		})
+		if err != nil {
+			managerLog.Error(err, "failed to start producer", "name", key.GetName())
+		}
+	}()
+}
+
 // Start satisfies the runnable interface and started by the Operator SDK manager.
 func (m *Manager) Start(ctx context.Context) error {
 	logger := log.FromContext(ctx)
@@ -102,26 +189,32 @@ func (m *Manager) Start(ctx context.Context) error {
 	return nil
 }
 
-// startProducers starts all producers and throws an error if any fail to start.
-func (m *Manager) startProducers() error {
+// startProducers starts all producers that were added before the manager started.
+func (m *Manager) startProducers() {
 	m.producersMux.Lock()
 	defer m.producersMux.Unlock()
 
 	m.shutdown = make(chan struct{})
 
-	if len(m.producers) > 0 {
-		for key, producer := range m.producers {
-			err := retry.OnError(retry.DefaultRetry, ALWAYS_TRUE, func() error {
-				return producer.Start(m.shutdown)
-			})
-			if err != nil {
-				managerLog.Error(err, "failed to start manager", "name", key.GetName())
-				return err
-			}
-		}
+	for key, producer := range m.producers {
+		m.start(key, producer)
 	}
+}
 
-	return nil
+// kindManager manages the event producers of a single kind
+type kindManager struct {
+	*Manager
+	kind string
+}
+
+// Remove remove the event producer of this kind
+func (k *kindManager) Remove(name types.NamespacedName) error {
+	return k.Manager.remove(k.kind, name)
+}
+
+// Exists returns true if the event producer of this kind has already been registered
+func (k *kindManager) Exists(objectMeta metav1.ObjectMeta) bool {
+	return k.Manager.exists(manager.ToKindKey(k.kind, objectMeta))
 }
 
 // InjectClient injectable client
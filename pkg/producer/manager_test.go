@@ -25,6 +25,22 @@ func TestManager_Remove(t *testing.T) {
 	assert.Equal(t, 0, len(mgr.producers))
 }
 
+func TestManager_RemoveStopsProducer(t *testing.T) {
+	mgr := getManager(t)
+
+	stopped := make(chan struct{})
+	source := &StoppableEventSource{stopped: stopped}
+	assert.NoError(t, mgr.Add(source))
+
+	mgr.Remove(source.Key().NamespacedName)
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second * 5):
+		t.Fatal("producer was not stopped when removed")
+	}
+}
+
 func TestManager_Exists(t *testing.T) {
 	mgr := getManager(t)
 	assert.NotNil(t, mgr)
@@ -48,6 +64,50 @@ func TestManager_Add(t *testing.T) {
 	assert.NotNil(t, mgr)
 }
 
+func TestManager_CanVerify(t *testing.T) {
+	mgr := getManager(t)
+
+	verifiable := &VerifiableEventSource{}
+	assert.NoError(t, mgr.Add(verifiable))
+
+	assert.True(t, mgr.CanVerify(events.Event{Source: verifiable.Key().NamespacedName}))
+	assert.False(t, mgr.CanVerify(events.Event{Source: dummySource.Key().NamespacedName}))
+	assert.False(t, mgr.CanVerify(events.Event{Source: types.NamespacedName{Namespace: "ns", Name: "trigger"}}))
+
+	_, err := mgr.IsActive(events.Event{Source: dummySource.Key().NamespacedName})
+	assert.Error(t, err)
+}
+
+func TestManager_ForKind(t *testing.T) {
+	mgr := getManager(t)
+
+	meta := v1.ObjectMeta{Name: "shared", Namespace: "ns", Generation: 1}
+	prometheus := mgr.ForKind("Prometheus")
+	podStatus := mgr.ForKind("PodStatus")
+
+	verifiable := &VerifiableKindEventSource{KindEventSource{key: manager.ToKindKey("Prometheus", meta)}}
+	assert.NoError(t, prometheus.Add(verifiable))
+	assert.NoError(t, podStatus.Add(&KindEventSource{key: manager.ToKindKey("PodStatus", meta)}))
+
+	assert.True(t, prometheus.Exists(meta))
+	assert.True(t, podStatus.Exists(meta))
+	assert.False(t, mgr.Exists(meta))
+
+	nn := types.NamespacedName{Namespace: "ns", Name: "shared"}
+	assert.True(t, mgr.CanVerify(events.Event{Source: nn, SourceKind: "Prometheus"}))
+	assert.False(t, mgr.CanVerify(events.Event{Source: nn, SourceKind: "PodStatus"}))
+	assert.False(t, mgr.CanVerify(events.Event{Source: nn}))
+
+	active, err := mgr.IsActive(events.Event{Source: nn, SourceKind: "Prometheus"})
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	assert.NoError(t, podStatus.Remove(nn))
+	assert.False(t, podStatus.Exists(meta))
+	assert.True(t, prometheus.Exists(meta))
+	assert.True(t, mgr.CanVerify(events.Event{Source: nn, SourceKind: "Prometheus"}))
+}
+
 func getManager(t *testing.T) *Manager {
 	mgr := &Manager{
 		eventBus:  eventbus.NewEventBus(1),
@@ -89,3 +149,53 @@ func (d *DummyEventSource) Start(ch <-chan struct{}) error {
 	<-ch
 	return nil
 }
+
+type StoppableEventSource struct {
+	DummyEventSource
+	stopped chan struct{}
+}
+
+func (d *StoppableEventSource) Key() manager.ObjectKey {
+	return manager.ObjectKey{
+		NamespacedName: types.NamespacedName{Namespace: "ns", Name: "stoppable"},
+		Generation:     1,
+	}
+}
+
+func (d *StoppableEventSource) Start(ch <-chan struct{}) error {
+	<-ch
+	close(d.stopped)
+	return nil
+}
+
+type VerifiableEventSource struct {
+	DummyEventSource
+}
+
+func (d *VerifiableEventSource) Key() manager.ObjectKey {
+	return manager.ObjectKey{
+		NamespacedName: types.NamespacedName{Namespace: "ns", Name: "verifiable"},
+		Generation:     1,
+	}
+}
+
+func (d *VerifiableEventSource) IsActive(events.Event) (bool, error) {
+	return true, nil
+}
+
+type KindEventSource struct {
+	DummyEventSource
+	key manager.ObjectKey
+}
+
+func (d *KindEventSource) Key() manager.ObjectKey {
+	return d.key
+}
+
+type VerifiableKindEventSource struct {
+	KindEventSource
+}
+
+func (d *VerifiableKindEventSource) IsActive(events.Event) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,34 @@
+package producer
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WatchNamespace the namespace a watch-based producer watches, the namespace of its event source
+// unless the namespaces are chosen by a selector, in which case all namespaces are watched.
+func WatchNamespace(sourceNamespace string, selector labels.Selector) string {
+	if selector != nil {
+		return metav1.NamespaceAll
+	}
+	return sourceNamespace
+}
+
+// SelectsNamespace checks the labels of the namespace match the selector, a nil selector
+// selects every namespace.
+func SelectsNamespace(ctx context.Context, reader client.Reader, selector labels.Selector, namespace string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := reader.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return false, err
+	}
+
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
@@ -0,0 +1,80 @@
+package nodecondition
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// LabelPrefix prefixes the EventData keys holding the labels of the Node
+const LabelPrefix = "labels."
+
+// Triggers decide which events the conditions of a Node publish.
+type Triggers struct {
+	Conditions []v1alpha1.NodeConditionTrigger
+	// Selector of the Nodes, every Node is selected when it's nil
+	Selector labels.Selector
+}
+
+// NewTriggers creates the triggers of the NodeCondition event source.
+func NewTriggers(spec v1alpha1.NodeConditionSpec) (*Triggers, error) {
+	if len(spec.Conditions) == 0 {
+		return nil, fmt.Errorf("at least one condition is required")
+	}
+
+	triggers := &Triggers{Conditions: spec.Conditions}
+
+	if spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %w", err)
+		}
+		triggers.Selector = selector
+	}
+
+	return triggers, nil
+}
+
+// Evaluate returns the events for the conditions the Node has held longer than their duration.
+func (t *Triggers) Evaluate(node *corev1.Node, now time.Time) []events.Event {
+	found := make([]events.Event, 0)
+
+	for _, trigger := range t.Conditions {
+		for _, cond := range node.Status.Conditions {
+			if string(cond.Type) != trigger.Type || cond.Status != trigger.Status {
+				continue
+			}
+
+			if now.Sub(cond.LastTransitionTime.Time) > trigger.For.Duration {
+				found = append(found, toEvent(node, trigger.GetEventName(), cond))
+			}
+		}
+	}
+
+	return found
+}
+
+// toEvent creates the event for the condition, the name and labels of the Node and the
+// reason and message of the condition are the data of the event.
+func toEvent(node *corev1.Node, name string, cond corev1.NodeCondition) events.Event {
+	data := make(events.EventData, len(node.Labels)+5)
+	for label, value := range node.Labels {
+		data[LabelPrefix+label] = value
+	}
+	data["node"] = node.Name
+	data["condition"] = string(cond.Type)
+	data["status"] = string(cond.Status)
+	data["reason"] = cond.Reason
+	data["message"] = cond.Message
+
+	return events.Event{
+		Name:       name,
+		ActiveTime: cond.LastTransitionTime.Time,
+		Data:       &data,
+	}
+}
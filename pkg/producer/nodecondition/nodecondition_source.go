@@ -0,0 +1,217 @@
+package nodecondition
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilwait "k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer"
+)
+
+var nodeConditionLogger = ctrl.Log.WithName("nodecondition_eventsource")
+
+type NodeConditionConfig struct {
+	Key      manager.ObjectKey
+	Triggers *Triggers
+	// Clientset watches the Nodes
+	Clientset kubernetes.Interface
+	// CheckInterval how often all the Nodes are evaluated, since a condition holding
+	// long enough doesn't change the Node
+	CheckInterval time.Duration
+}
+
+// EventProducer publishes events when the conditions of the watched Nodes hold long enough.
+type EventProducer struct {
+	config   NodeConditionConfig
+	producer producer.EventProducer
+
+	mux   sync.Mutex
+	store cache.Store
+	// names of the events published for each Node that are still active
+	published map[string]sets.String
+}
+
+var _ producer.KeyedEventProducer = &EventProducer{}
+var _ producer.EventVerifier = &EventProducer{}
+
+// NewEventProducer creation function for a new NodeCondition EventProducer
+func NewEventProducer(cfg NodeConditionConfig, prd producer.EventProducer) *EventProducer {
+	return &EventProducer{
+		config:    cfg,
+		producer:  prd,
+		published: make(map[string]sets.String),
+	}
+}
+
+// Start called to start watching the Nodes, the watch stops when the done channel closes.
+func (d *EventProducer) Start(done <-chan struct{}) error {
+	informer := cache.NewSharedIndexInformer(d.listWatch(), &corev1.Node{}, 0, cache.Indexers{})
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: d.onNode,
+		UpdateFunc: func(_, newObj interface{}) {
+			d.onNode(newObj)
+		},
+		DeleteFunc: d.forget,
+	})
+
+	d.mux.Lock()
+	d.store = informer.GetStore()
+	d.mux.Unlock()
+
+	if d.config.CheckInterval > 0 {
+		go utilwait.Until(d.check, d.config.CheckInterval, done)
+	}
+
+	nodeConditionLogger.Info("starting node condition watch", "name", d.getName())
+	informer.Run(done)
+	nodeConditionLogger.Info("stopping node condition watch", "name", d.getName())
+	return nil
+}
+
+// Publish send the event to the bus, retrying on any errors
+func (d *EventProducer) Publish(topic string, event events.Event) error {
+	return retry.OnError(retry.DefaultBackoff, func(err error) bool { return true }, func() error {
+		return d.producer.Publish(topic, event)
+	})
+}
+
+// listWatch watches the selected Nodes
+func (d *EventProducer) listWatch() *cache.ListWatch {
+	nodes := d.config.Clientset.CoreV1().Nodes()
+
+	selector := ""
+	if d.config.Triggers.Selector != nil {
+		selector = d.config.Triggers.Selector.String()
+	}
+
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = selector
+			return nodes.List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = selector
+			return nodes.Watch(context.Background(), options)
+		},
+	}
+}
+
+// check evaluates all the Nodes, publishing the conditions that held long enough since the Nodes last changed
+func (d *EventProducer) check() {
+	d.mux.Lock()
+	store := d.store
+	d.mux.Unlock()
+
+	for _, obj := range store.List() {
+		d.onNode(obj)
+	}
+}
+
+// onNode publishes the events for the conditions of the Node that weren't already published.
+func (d *EventProducer) onNode(obj interface{}) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return
+	}
+
+	toPublish := d.observe(node, time.Now())
+
+	name := d.getName()
+	for _, event := range toPublish {
+		event.Source = name
+		event.SourceKind = d.config.Key.Kind
+		topic := events.CreateFullyQualifiedTopicName(event.Name, name)
+		if err := d.Publish(topic, event); err != nil {
+			nodeConditionLogger.Error(err, fmt.Sprintf("failed to publish event %v", event.Name))
+		}
+	}
+}
+
+// observe returns the events of the Node that became active since it was last observed, the
+// events are tracked by name since the reason, message or labels may change while it's active.
+func (d *EventProducer) observe(node *corev1.Node, now time.Time) []events.Event {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	published := d.published[node.Name]
+	active := sets.NewString()
+	toPublish := make([]events.Event, 0)
+
+	for _, event := range d.config.Triggers.Evaluate(node, now) {
+		active.Insert(event.Name)
+		if !published.Has(event.Name) {
+			toPublish = append(toPublish, event)
+		}
+	}
+
+	// only the events that are still active are remembered, so they're published again
+	// once the condition holds again
+	d.published[node.Name] = active
+	return toPublish
+}
+
+// forget drops the published events of a deleted Node
+func (d *EventProducer) forget(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return
+	}
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	delete(d.published, node.Name)
+}
+
+// IsActive checks if the Node of the event still holds the condition that published it.
+func (d *EventProducer) IsActive(event events.Event) (bool, error) {
+	if event.Data == nil {
+		return false, nil
+	}
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if d.store == nil {
+		return false, fmt.Errorf("node condition event source '%s' is not started", d.getName())
+	}
+
+	obj, exists, err := d.store.GetByKey(event.Data.Get("node"))
+	if err != nil || !exists {
+		return false, err
+	}
+
+	for _, active := range d.config.Triggers.Evaluate(obj.(*corev1.Node), time.Now()) {
+		if active.Name == event.Name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (d *EventProducer) Key() manager.ObjectKey {
+	return d.config.Key
+}
+
+func (d *EventProducer) getName() types.NamespacedName {
+	return d.config.Key.NamespacedName
+}
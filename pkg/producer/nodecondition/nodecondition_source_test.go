@@ -0,0 +1,135 @@
+package nodecondition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newNode(name string, conditions ...corev1.NodeCondition) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"topology.kubernetes.io/zone": "us-east-1a"},
+		},
+		Status: corev1.NodeStatus{Conditions: conditions},
+	}
+}
+
+func condition(condType corev1.NodeConditionType, status corev1.ConditionStatus, since time.Time) corev1.NodeCondition {
+	return corev1.NodeCondition{
+		Type:               condType,
+		Status:             status,
+		LastTransitionTime: metav1.NewTime(since),
+		Reason:             "KubeletNotReady",
+		Message:            "container runtime is down",
+	}
+}
+
+var testSpec = v1alpha1.NodeConditionSpec{
+	Conditions: []v1alpha1.NodeConditionTrigger{
+		{Type: "Ready", Status: corev1.ConditionFalse, For: metav1.Duration{Duration: time.Minute}},
+		{Type: "KernelDeadlock", Status: corev1.ConditionTrue, For: metav1.Duration{Duration: 0}, EventName: "KernelDeadlock"},
+	},
+}
+
+func TestTriggers_Evaluate(t *testing.T) {
+	triggers, err := NewTriggers(testSpec)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	node := newNode("node-1",
+		condition(corev1.NodeReady, corev1.ConditionFalse, now.Add(-2*time.Minute)),
+		condition("KernelDeadlock", corev1.ConditionFalse, now.Add(-time.Hour)),
+	)
+
+	found := triggers.Evaluate(node, now)
+	assert.Equal(t, 1, len(found))
+	assert.Equal(t, "NodeReadyFalse", found[0].Name)
+	assert.Equal(t, "node-1", found[0].Data.Get("node"))
+	assert.Equal(t, "us-east-1a", found[0].Data.Get("labels.topology.kubernetes.io/zone"))
+	assert.Equal(t, "KubeletNotReady", found[0].Data.Get("reason"))
+	assert.Equal(t, "container runtime is down", found[0].Data.Get("message"))
+
+	// not held long enough
+	node.Status.Conditions[0].LastTransitionTime = metav1.NewTime(now.Add(-time.Second))
+	assert.Empty(t, triggers.Evaluate(node, now))
+
+	node.Status.Conditions[1].Status = corev1.ConditionTrue
+	found = triggers.Evaluate(node, now)
+	assert.Equal(t, 1, len(found))
+	assert.Equal(t, "KernelDeadlock", found[0].Name)
+
+	_, err = NewTriggers(v1alpha1.NodeConditionSpec{})
+	assert.Error(t, err)
+}
+
+func TestEventProducer_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	eventBus := eventbus.NewEventBus(1)
+	eventBus.InjectLogger(testr.New(t))
+	go eventBus.Start(ctx)
+
+	source := types.NamespacedName{Namespace: "ns1", Name: "ncs1"}
+	eventCh, err := eventBus.Subscribe(events.CreateFullyQualifiedTopicName("NodeReadyFalse", source))
+	assert.NoError(t, err)
+
+	// the node just became unready, so the condition holds long enough only on a later check
+	node := newNode("node-1", condition(corev1.NodeReady, corev1.ConditionFalse, time.Now().Add(-time.Minute+200*time.Millisecond)))
+	clientset := fake.NewSimpleClientset(node)
+
+	triggers, err := NewTriggers(testSpec)
+	assert.NoError(t, err)
+
+	eventsource := NewEventProducer(NodeConditionConfig{
+		Key:           manager.ToKey(metav1.ObjectMeta{Namespace: source.Namespace, Name: source.Name, Generation: 1}),
+		Triggers:      triggers,
+		Clientset:     clientset,
+		CheckInterval: 50 * time.Millisecond,
+	}, eventBus)
+	assert.Equal(t, "ns1/ncs1", eventsource.Key().GetName())
+
+	go eventsource.Start(ctx.Done())
+
+	var received events.Event
+	select {
+	case received = <-eventCh.OnEvent():
+		assert.Equal(t, "NodeReadyFalse", received.Name)
+		assert.Equal(t, source, received.Source)
+		assert.Equal(t, "node-1", received.Data.Get("node"))
+	case <-time.After(time.Second * 5):
+		t.Fatal("event never arrived")
+	}
+
+	active, err := eventsource.IsActive(received)
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	// published once while the condition holds
+	select {
+	case event := <-eventCh.OnEvent():
+		t.Fatalf("unexpected event %v", event.Data)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	ready := newNode("node-1", condition(corev1.NodeReady, corev1.ConditionTrue, time.Now()))
+	_, err = clientset.CoreV1().Nodes().UpdateStatus(ctx, ready, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		active, err := eventsource.IsActive(received)
+		return err == nil && !active
+	}, 5*time.Second, 10*time.Millisecond)
+}
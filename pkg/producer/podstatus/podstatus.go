@@ -0,0 +1,182 @@
+package podstatus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// EventRestarts the name of the event published when a container restarts too often
+	EventRestarts = "Restarts"
+	// EventUnready the name of the event published when a Pod stays unready too long
+	EventUnready = "Unready"
+)
+
+// Triggers decide which events the status of a Pod publishes.
+type Triggers struct {
+	Reasons       sets.String
+	RestartCount  int32
+	RestartWindow time.Duration
+	UnreadyFor    time.Duration
+	// Selector of the Pods, every Pod is selected when it's nil
+	Selector labels.Selector
+	// NamespaceSelector selects the namespaces of the Pods, nil when only the
+	// Pods of a single namespace are watched.
+	NamespaceSelector labels.Selector
+}
+
+// NewTriggers creates the triggers of the PodStatus event source.
+func NewTriggers(spec v1alpha1.PodStatusSpec) (*Triggers, error) {
+	triggers := &Triggers{
+		Reasons: sets.NewString(spec.Reasons...),
+	}
+
+	if spec.Restarts != nil {
+		if spec.Restarts.Window.Duration <= 0 {
+			return nil, fmt.Errorf("restarts window must be positive")
+		}
+		triggers.RestartCount = spec.Restarts.Count
+		triggers.RestartWindow = spec.Restarts.Window.Duration
+	}
+
+	if spec.UnreadyFor != nil {
+		triggers.UnreadyFor = spec.UnreadyFor.Duration
+	}
+
+	if triggers.Reasons.Len() == 0 && triggers.RestartWindow == 0 && triggers.UnreadyFor == 0 {
+		return nil, fmt.Errorf("at least one of reasons, restarts or unreadyFor is required")
+	}
+
+	if spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %w", err)
+		}
+		triggers.Selector = selector
+	}
+
+	if spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+		triggers.NamespaceSelector = selector
+	}
+
+	return triggers, nil
+}
+
+// Evaluate returns the events for the triggers the Pod currently meets, restarts are the times
+// the restarts of each container were observed.
+func (t *Triggers) Evaluate(pod *corev1.Pod, restarts map[string][]time.Time, now time.Time) []events.Event {
+	found := make([]events.Event, 0)
+
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, status := range statuses {
+		for _, reason := range containerReasons(status) {
+			if t.Reasons.Has(reason) {
+				found = append(found, toEvent(pod, status.Name, reason, now))
+			}
+		}
+
+		if t.RestartWindow > 0 && int32(countSince(restarts[status.Name], now.Add(-t.RestartWindow))) > t.RestartCount {
+			found = append(found, toEvent(pod, status.Name, EventRestarts, now))
+		}
+	}
+
+	if since, ok := t.unreadySince(pod, now); ok {
+		unready := 0
+		for _, status := range pod.Status.ContainerStatuses {
+			if !status.Ready {
+				found = append(found, toEvent(pod, status.Name, EventUnready, since))
+				unready++
+			}
+		}
+
+		// unready because of a readiness gate rather than a container
+		if unready == 0 {
+			found = append(found, toEvent(pod, "", EventUnready, since))
+		}
+	}
+
+	return found
+}
+
+// unreadySince returns when a running Pod became unready, if it's been unready for longer than the threshold.
+func (t *Triggers) unreadySince(pod *corev1.Pod, now time.Time) (time.Time, bool) {
+	if t.UnreadyFor <= 0 || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return time.Time{}, false
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue {
+			since := cond.LastTransitionTime.Time
+			return since, now.Sub(since) > t.UnreadyFor
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// containerReasons the reasons of the current state of the container and of its last termination.
+func containerReasons(status corev1.ContainerStatus) []string {
+	reasons := sets.NewString()
+
+	if status.State.Waiting != nil && len(status.State.Waiting.Reason) > 0 {
+		reasons.Insert(status.State.Waiting.Reason)
+	}
+	if status.State.Terminated != nil && len(status.State.Terminated.Reason) > 0 {
+		reasons.Insert(status.State.Terminated.Reason)
+	}
+	if status.LastTerminationState.Terminated != nil && len(status.LastTerminationState.Terminated.Reason) > 0 {
+		reasons.Insert(status.LastTerminationState.Terminated.Reason)
+	}
+
+	return reasons.List()
+}
+
+// countSince counts the times after the cutoff
+func countSince(times []time.Time, cutoff time.Time) int {
+	count := 0
+	for _, t := range times {
+		if t.After(cutoff) {
+			count++
+		}
+	}
+	return count
+}
+
+// toEvent creates an event named after the reason, the Pod, the container, where it runs and what
+// controls it are the data of the event.
+func toEvent(pod *corev1.Pod, container, reason string, activeTime time.Time) events.Event {
+	data := events.EventData{
+		"pod":       pod.Name,
+		"container": container,
+		"namespace": pod.Namespace,
+		"node":      pod.Spec.NodeName,
+		"ownerKind": "",
+		"ownerName": "",
+		"reason":    reason,
+	}
+
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		data["ownerKind"] = owner.Kind
+		data["ownerName"] = owner.Name
+	}
+
+	return events.Event{
+		Name:       reason,
+		ActiveTime: activeTime,
+		Data:       &data,
+	}
+}
@@ -0,0 +1,296 @@
+package podstatus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilwait "k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer"
+)
+
+var podStatusLogger = ctrl.Log.WithName("podstatus_eventsource")
+
+type PodStatusConfig struct {
+	Key      manager.ObjectKey
+	Triggers *Triggers
+	// Clientset watches the Pods
+	Clientset kubernetes.Interface
+	// Namespaces reads the labels of the namespaces matched against the namespace selector
+	Namespaces client.Reader
+	// CheckInterval how often all the Pods are evaluated, for the triggers that
+	// are met without the Pod changing
+	CheckInterval time.Duration
+}
+
+// containerHistory the restarts observed for a container
+type containerHistory struct {
+	restartCount int32
+	restarts     []time.Time
+	// keys of the events published for the container that are still active
+	published sets.String
+}
+
+// EventProducer publishes events when the containers of the watched Pods meet the triggers.
+type EventProducer struct {
+	config   PodStatusConfig
+	producer producer.EventProducer
+
+	mux     sync.Mutex
+	store   cache.Store
+	history map[types.UID]map[string]*containerHistory
+}
+
+var _ producer.KeyedEventProducer = &EventProducer{}
+var _ producer.EventVerifier = &EventProducer{}
+
+// NewEventProducer creation function for a new PodStatus EventProducer
+func NewEventProducer(cfg PodStatusConfig, prd producer.EventProducer) *EventProducer {
+	return &EventProducer{
+		config:   cfg,
+		producer: prd,
+		history:  make(map[types.UID]map[string]*containerHistory),
+	}
+}
+
+// Start called to start watching the Pods, the watch stops when the done channel closes.
+func (d *EventProducer) Start(done <-chan struct{}) error {
+	informer := cache.NewSharedIndexInformer(d.listWatch(), &corev1.Pod{}, 0, cache.Indexers{})
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: d.onPod,
+		UpdateFunc: func(_, newObj interface{}) {
+			d.onPod(newObj)
+		},
+		DeleteFunc: d.forget,
+	})
+
+	d.mux.Lock()
+	d.store = informer.GetStore()
+	d.mux.Unlock()
+
+	if d.config.CheckInterval > 0 {
+		go utilwait.Until(d.check, d.config.CheckInterval, done)
+	}
+
+	podStatusLogger.Info("starting pod status watch", "name", d.getName())
+	informer.Run(done)
+	podStatusLogger.Info("stopping pod status watch", "name", d.getName())
+	return nil
+}
+
+// Publish send the event to the bus, retrying on any errors
+func (d *EventProducer) Publish(topic string, event events.Event) error {
+	return retry.OnError(retry.DefaultBackoff, func(err error) bool { return true }, func() error {
+		return d.producer.Publish(topic, event)
+	})
+}
+
+// listWatch watches the selected Pods of the namespace of the event source, or of all
+// namespaces when the namespaces are chosen by a selector.
+func (d *EventProducer) listWatch() *cache.ListWatch {
+	namespace := producer.WatchNamespace(d.getName().Namespace, d.config.Triggers.NamespaceSelector)
+	pods := d.config.Clientset.CoreV1().Pods(namespace)
+
+	selector := ""
+	if d.config.Triggers.Selector != nil {
+		selector = d.config.Triggers.Selector.String()
+	}
+
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = selector
+			return pods.List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = selector
+			return pods.Watch(context.Background(), options)
+		},
+	}
+}
+
+// check evaluates all the Pods, publishing the triggers met since the Pods last changed
+func (d *EventProducer) check() {
+	d.mux.Lock()
+	store := d.store
+	d.mux.Unlock()
+
+	for _, obj := range store.List() {
+		d.onPod(obj)
+	}
+}
+
+// onPod publishes the events for the triggers the Pod meets that weren't already published.
+func (d *EventProducer) onPod(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+
+	selected, err := producer.SelectsNamespace(context.Background(), d.config.Namespaces, d.config.Triggers.NamespaceSelector, pod.Namespace)
+	if err != nil {
+		podStatusLogger.Error(err, "failed to read namespace", "namespace", pod.Namespace)
+		return
+	}
+	if !selected {
+		return
+	}
+
+	toPublish := d.observe(pod, time.Now())
+
+	name := d.getName()
+	for _, event := range toPublish {
+		event.Source = name
+		event.SourceKind = d.config.Key.Kind
+		topic := events.CreateFullyQualifiedTopicName(event.Name, name)
+		if err := d.Publish(topic, event); err != nil {
+			podStatusLogger.Error(err, fmt.Sprintf("failed to publish event %v", event.Name))
+		}
+	}
+}
+
+// observe records the restarts of the containers of the Pod and returns the events that
+// became active since the Pod was last observed.
+func (d *EventProducer) observe(pod *corev1.Pod, now time.Time) []events.Event {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	containers, ok := d.history[pod.UID]
+	if !ok {
+		containers = make(map[string]*containerHistory)
+		d.history[pod.UID] = containers
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		h, ok := containers[status.Name]
+		if !ok {
+			// restarts from before the container was first observed aren't counted
+			containers[status.Name] = &containerHistory{restartCount: status.RestartCount, published: sets.NewString()}
+			continue
+		}
+
+		if status.RestartCount > h.restartCount {
+			for i := h.restartCount; i < status.RestartCount; i++ {
+				h.restarts = append(h.restarts, now)
+			}
+			h.restartCount = status.RestartCount
+			// a new termination, so the events of the container are published again
+			h.published = sets.NewString()
+		}
+
+		if d.config.Triggers.RestartWindow > 0 {
+			h.restarts = h.restarts[len(h.restarts)-countSince(h.restarts, now.Add(-d.config.Triggers.RestartWindow)):]
+		} else {
+			h.restarts = nil
+		}
+	}
+
+	if _, ok := containers[""]; !ok {
+		containers[""] = &containerHistory{published: sets.NewString()}
+	}
+
+	active := make(map[string]sets.String, len(containers))
+	for container := range containers {
+		active[container] = sets.NewString()
+	}
+
+	toPublish := make([]events.Event, 0)
+	for _, event := range d.config.Triggers.Evaluate(pod, restartTimes(containers), now) {
+		container := event.Data.Get("container")
+		h, ok := containers[container]
+		if !ok {
+			continue
+		}
+
+		key := event.Key()
+		active[container].Insert(key)
+		if !h.published.Has(key) {
+			toPublish = append(toPublish, event)
+		}
+	}
+
+	// only the events that are still active are remembered, so they're published again
+	// once they become active again
+	for container, h := range containers {
+		h.published = active[container]
+	}
+
+	return toPublish
+}
+
+// forget drops the history of a deleted Pod
+func (d *EventProducer) forget(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	delete(d.history, pod.UID)
+}
+
+// IsActive checks if the Pod of the event still meets the trigger that published it.
+func (d *EventProducer) IsActive(event events.Event) (bool, error) {
+	if event.Data == nil {
+		return false, nil
+	}
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if d.store == nil {
+		return false, fmt.Errorf("pod status event source '%s' is not started", d.getName())
+	}
+
+	obj, exists, err := d.store.GetByKey(event.Data.Get("namespace") + "/" + event.Data.Get("pod"))
+	if err != nil || !exists {
+		return false, err
+	}
+
+	pod := obj.(*corev1.Pod)
+	key := event.Key()
+	for _, active := range d.config.Triggers.Evaluate(pod, restartTimes(d.history[pod.UID]), time.Now()) {
+		if active.Key() == key {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (d *EventProducer) Key() manager.ObjectKey {
+	return d.config.Key
+}
+
+func (d *EventProducer) getName() types.NamespacedName {
+	return d.config.Key.NamespacedName
+}
+
+// restartTimes the times the restarts of each container were observed
+func restartTimes(containers map[string]*containerHistory) map[string][]time.Time {
+	times := make(map[string][]time.Time, len(containers))
+	for container, h := range containers {
+		times[container] = h.restarts
+	}
+	return times
+}
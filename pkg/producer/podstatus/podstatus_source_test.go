@@ -0,0 +1,170 @@
+package podstatus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPod(name string, statuses ...corev1.ContainerStatus) *corev1.Pod {
+	controller := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      name,
+			UID:       types.UID(name + "-uid"),
+			Labels:    map[string]string{"app": "test-app"},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "app-5d9c7", Controller: &controller},
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: "node-1"},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: statuses,
+		},
+	}
+}
+
+func crashLooping(restarts int32) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name:         "app",
+		RestartCount: restarts,
+		State: corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+		},
+		LastTerminationState: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+		},
+	}
+}
+
+func TestTriggers_Evaluate(t *testing.T) {
+	triggers, err := NewTriggers(v1alpha1.PodStatusSpec{
+		Reasons:    []string{"CrashLoopBackOff", "OOMKilled"},
+		Restarts:   &v1alpha1.RestartThreshold{Count: 2, Window: metav1.Duration{Duration: time.Minute}},
+		UnreadyFor: &metav1.Duration{Duration: time.Minute},
+	})
+	assert.NoError(t, err)
+
+	now := time.Now()
+	pod := newPod("app-pod-xyxsl", crashLooping(3))
+	pod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(now.Add(-2 * time.Minute))},
+	}
+
+	restarts := map[string][]time.Time{"app": {now.Add(-2 * time.Minute), now.Add(-30 * time.Second), now.Add(-20 * time.Second), now.Add(-10 * time.Second)}}
+	found := triggers.Evaluate(pod, restarts, now)
+
+	names := make([]string, 0, len(found))
+	for _, event := range found {
+		names = append(names, event.Name)
+	}
+	assert.ElementsMatch(t, []string{"CrashLoopBackOff", "OOMKilled", EventRestarts, EventUnready}, names)
+
+	data := *found[0].Data
+	assert.Equal(t, "app-pod-xyxsl", data["pod"])
+	assert.Equal(t, "app", data["container"])
+	assert.Equal(t, "ns1", data["namespace"])
+	assert.Equal(t, "node-1", data["node"])
+	assert.Equal(t, "ReplicaSet", data["ownerKind"])
+	assert.Equal(t, "app-5d9c7", data["ownerName"])
+
+	// the restarts outside the window and a pod that just became unready don't trigger
+	pod.Status.Conditions[0].LastTransitionTime = metav1.NewTime(now.Add(-time.Second))
+	restarts["app"] = restarts["app"][:2]
+	found = triggers.Evaluate(pod, restarts, now)
+	assert.Equal(t, 2, len(found))
+
+	_, err = NewTriggers(v1alpha1.PodStatusSpec{})
+	assert.Error(t, err)
+}
+
+func TestEventProducer_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	eventBus := eventbus.NewEventBus(1)
+	eventBus.InjectLogger(testr.New(t))
+	go eventBus.Start(ctx)
+
+	source := types.NamespacedName{Namespace: "ns1", Name: "pss1"}
+	eventCh, err := eventBus.Subscribe(events.CreateFullyQualifiedTopicName("CrashLoopBackOff", source))
+	assert.NoError(t, err)
+
+	healthy := newPod("app-pod-xyxsl", corev1.ContainerStatus{
+		Name:  "app",
+		Ready: true,
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	})
+	clientset := fake.NewSimpleClientset(healthy)
+
+	triggers, err := NewTriggers(v1alpha1.PodStatusSpec{
+		Reasons:  []string{"CrashLoopBackOff"},
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test-app"}},
+	})
+	assert.NoError(t, err)
+
+	eventsource := NewEventProducer(PodStatusConfig{
+		Key:        manager.ToKey(metav1.ObjectMeta{Namespace: source.Namespace, Name: source.Name, Generation: 1}),
+		Triggers:   triggers,
+		Clientset:  clientset,
+		Namespaces: clientfake.NewClientBuilder().Build(),
+	}, eventBus)
+	assert.Equal(t, "ns1/pss1", eventsource.Key().GetName())
+
+	go eventsource.Start(ctx.Done())
+
+	assert.Eventually(t, func() bool {
+		eventsource.mux.Lock()
+		defer eventsource.mux.Unlock()
+		_, ok := eventsource.history[healthy.UID]
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	crashing := newPod("app-pod-xyxsl", crashLooping(1))
+	_, err = clientset.CoreV1().Pods("ns1").UpdateStatus(ctx, crashing, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	var received events.Event
+	select {
+	case received = <-eventCh.OnEvent():
+		assert.Equal(t, "CrashLoopBackOff", received.Name)
+		assert.Equal(t, source, received.Source)
+		assert.Equal(t, "app-pod-xyxsl", received.Data.Get("pod"))
+		assert.Equal(t, "app", received.Data.Get("container"))
+	case <-time.After(time.Second * 5):
+		t.Fatal("event never arrived")
+	}
+
+	active, err := eventsource.IsActive(received)
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	// the same status isn't published again
+	eventsource.check()
+	select {
+	case event := <-eventCh.OnEvent():
+		t.Fatalf("unexpected event %v", event.Data)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	_, err = clientset.CoreV1().Pods("ns1").UpdateStatus(ctx, healthy, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		active, err := eventsource.IsActive(received)
+		return err == nil && !active
+	}, 5*time.Second, 10*time.Millisecond)
+}
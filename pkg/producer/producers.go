@@ -20,3 +20,15 @@ type EventProducer interface {
 	// Publish producers can publish events to a topic on the bus.
 	Publish(string, events.Event) error
 }
+
+type EventVerifier interface {
+	// IsActive returns true if a previously published event is still active at the source.
+	IsActive(events.Event) (bool, error)
+}
+
+// VerificationChecker is implemented by verifiers that can tell if an event can be verified at all,
+// before it needs to be.
+type VerificationChecker interface {
+	// CanVerify returns true if the source of the event can tell if the event is still active.
+	CanVerify(events.Event) bool
+}
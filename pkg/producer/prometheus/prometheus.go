@@ -2,8 +2,11 @@ package prometheus
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
@@ -29,18 +32,34 @@ func NewPrometheusService(api v1.API) *PrometheusService {
 	}
 }
 
-func NewPrometheusClient(address, username, password string) (*PrometheusService, error) {
-	clientConfig := api.Config{
-		Address: address,
-	}
+// ClientConfig defines the address of prometheus and how to connect to it
+type ClientConfig struct {
+	Address string
+	// Username and Password are the basic auth credentials
+	Username string
+	Password string
+	// BearerToken is sent in the Authorization header
+	BearerToken string
+	// CA is the PEM encoded bundle used to verify the server, the system roots when empty
+	CA []byte
+	// Cert and Key are the PEM encoded client certificate
+	Cert               []byte
+	Key                []byte
+	InsecureSkipVerify bool
+	// Headers are sent with every request
+	Headers map[string]string
+}
 
-	if len(username) > 0 {
-		clientConfig.RoundTripper = config.NewBasicAuthRoundTripper(username,
-			config.Secret(password), "",
-			api.DefaultRoundTripper)
+func NewPrometheusClient(cfg ClientConfig) (*PrometheusService, error) {
+	roundTripper, err := newRoundTripper(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating client, %w", err)
 	}
 
-	client, err := api.NewClient(clientConfig)
+	client, err := api.NewClient(api.Config{
+		Address:      cfg.Address,
+		RoundTripper: roundTripper,
+	})
 
 	if err != nil {
 		return nil, fmt.Errorf("error creating client, %w", err)
@@ -49,6 +68,68 @@ func NewPrometheusClient(address, username, password string) (*PrometheusService
 	return NewPrometheusService(v1.NewAPI(client)), nil
 }
 
+// newRoundTripper wraps the default round tripper with the TLS config, the credentials and the headers
+func newRoundTripper(cfg ClientConfig) (http.RoundTripper, error) {
+	roundTripper := api.DefaultRoundTripper
+
+	if len(cfg.CA) > 0 || len(cfg.Cert) > 0 || cfg.InsecureSkipVerify {
+		tlsConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: cfg.InsecureSkipVerify,
+		}
+
+		if len(cfg.CA) > 0 {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(cfg.CA) {
+				return nil, errors.New("no certificates found in the CA bundle")
+			}
+			tlsConfig.RootCAs = pool
+		}
+
+		if len(cfg.Cert) > 0 {
+			cert, err := tls.X509KeyPair(cfg.Cert, cfg.Key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+
+		transport := api.DefaultRoundTripper.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		roundTripper = transport
+	}
+
+	if len(cfg.Username) > 0 {
+		roundTripper = config.NewBasicAuthRoundTripper(cfg.Username,
+			config.Secret(cfg.Password), "",
+			roundTripper)
+	} else if len(cfg.BearerToken) > 0 {
+		roundTripper = config.NewAuthorizationCredentialsRoundTripper("Bearer",
+			config.Secret(cfg.BearerToken),
+			roundTripper)
+	}
+
+	if len(cfg.Headers) > 0 {
+		roundTripper = &headersRoundTripper{headers: cfg.Headers, next: roundTripper}
+	}
+
+	return roundTripper, nil
+}
+
+// headersRoundTripper sets the headers on every request
+type headersRoundTripper struct {
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+func (rt *headersRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range rt.headers {
+		req.Header.Set(name, value)
+	}
+	return rt.next.RoundTrip(req)
+}
+
 // ToEvents get the Events for the alert name.
 func (r *AlertQueryResult) ToEvents(includePending bool) ([]events.Event, error) {
 	foundAlerts := r.findActiveAlert(includePending)
@@ -3,6 +3,8 @@ package prometheus
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -23,20 +25,27 @@ type PrometheusConfig struct {
 	IncludePending bool
 	Key            manager.ObjectKey
 	Client         *PrometheusService
+	Queries        []Query
 }
 
 type EventProducer struct {
 	config   PrometheusConfig
 	producer producer.EventProducer
+
+	// when each series returned by the queries was first seen
+	seriesMux sync.Mutex
+	pending   map[string]time.Time
 }
 
 var _ producer.KeyedEventProducer = &EventProducer{}
+var _ producer.EventVerifier = &EventProducer{}
 
 // NewEventProducer creation function for a new Prometheus EventProducer
 func NewEventProducer(cfg PrometheusConfig, prd producer.EventProducer) *EventProducer {
 	return &EventProducer{
 		config:   cfg,
 		producer: prd,
+		pending:  make(map[string]time.Time),
 	}
 }
 
@@ -59,8 +68,14 @@ func (d *EventProducer) Publish(topic string, event events.Event) error {
 	})
 }
 
-// poll fetch alerts from each prometheus service and notify the callbacks on any active alerts
+// poll fetch alerts and evaluate the queries, publishing the active alerts and series
 func (d *EventProducer) poll() {
+	d.pollAlerts()
+	d.pollQueries()
+}
+
+// pollAlerts fetch alerts from each prometheus service and notify the callbacks on any active alerts
+func (d *EventProducer) pollAlerts() {
 
 	alerts, err := d.getClient().GetActiveAlerts()
 	if err != nil {
@@ -81,6 +96,66 @@ func (d *EventProducer) poll() {
 
 	for _, event := range eventsToPublish {
 		name := d.getName()
+		event.Source = name
+		event.SourceKind = d.config.Key.Kind
+		topic := events.CreateFullyQualifiedTopicName(event.Name, name)
+		if err := d.Publish(topic, event); err != nil {
+			prometheusLogger.Error(err, fmt.Sprintf("failed to publish event %v", event.Name))
+		}
+	}
+}
+
+// pollQueries evaluate the queries and publish the series that were returned for long enough,
+// the series are published on every poll, like the active alerts, leaving the duplicates to the
+// suppression policies of the countermeasures.
+func (d *EventProducer) pollQueries() {
+	if len(d.config.Queries) == 0 {
+		return
+	}
+
+	now := time.Now()
+	seen := make(map[string]struct{})
+	toPublish := make([]events.Event, 0)
+
+	for _, query := range d.config.Queries {
+		series, err := d.getClient().Query(query.Expr)
+		if err != nil {
+			prometheusLogger.Error(err, "failed to evaluate query", "prometheus_service", d.getName(), "query", query.Name)
+			// keep the series of the query so a failed evaluation doesn't restart their durations
+			d.keepSeries(query.Name, seen)
+			continue
+		}
+
+		d.seriesMux.Lock()
+		for _, s := range series {
+			key := seriesKey(query.Name, s.Metric)
+			seen[key] = struct{}{}
+
+			firstSeen, ok := d.pending[key]
+			if !ok {
+				firstSeen = now
+				d.pending[key] = now
+			}
+
+			if now.Sub(firstSeen) >= query.For {
+				toPublish = append(toPublish, s.ToEvent(query.Name, firstSeen))
+			}
+		}
+		d.seriesMux.Unlock()
+	}
+
+	d.seriesMux.Lock()
+	for key := range d.pending {
+		if _, ok := seen[key]; !ok {
+			delete(d.pending, key)
+		}
+	}
+	d.seriesMux.Unlock()
+
+	name := d.getName()
+	for _, event := range toPublish {
+		event.Source = name
+		event.SourceKind = d.config.Key.Kind
 		topic := events.CreateFullyQualifiedTopicName(event.Name, name)
 		if err := d.Publish(topic, event); err != nil {
 			prometheusLogger.Error(err, fmt.Sprintf("failed to publish event %v", event.Name))
@@ -88,6 +163,70 @@ func (d *EventProducer) poll() {
 	}
 }
 
+// keepSeries marks the series of the query as seen
+func (d *EventProducer) keepSeries(query string, seen map[string]struct{}) {
+	d.seriesMux.Lock()
+	defer d.seriesMux.Unlock()
+
+	prefix := query + "/"
+	for key := range d.pending {
+		if strings.HasPrefix(key, prefix) {
+			seen[key] = struct{}{}
+		}
+	}
+}
+
+// findQuery the query publishing the events with the name
+func (d *EventProducer) findQuery(name string) *Query {
+	for idx := range d.config.Queries {
+		if d.config.Queries[idx].Name == name {
+			return &d.config.Queries[idx]
+		}
+	}
+	return nil
+}
+
+// IsActive checks if the event is still firing (or pending) on the prometheus service, or
+// for the events of a query, if the query still returns the series.
+func (d *EventProducer) IsActive(event events.Event) (bool, error) {
+	if query := d.findQuery(event.Name); query != nil {
+		series, err := d.getClient().Query(query.Expr)
+		if err != nil {
+			return false, err
+		}
+
+		metric := metricOf(event)
+		for _, s := range series {
+			if s.Metric.Equal(metric) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	alerts, err := d.getClient().GetActiveAlerts()
+	if err != nil {
+		return false, err
+	}
+
+	activeEvents, err := alerts.ToEvents(d.config.IncludePending)
+	if err != nil {
+		if errors.Is(err, ErrAlertNotFiring) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	key := event.Key()
+	for _, active := range activeEvents {
+		if active.Key() == key {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (d *EventProducer) Key() manager.ObjectKey {
 	return d.config.Key
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
 	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
 	"github.com/go-logr/logr/testr"
 	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
@@ -133,3 +134,115 @@ func TestEventSource_poll(t *testing.T) {
 		t.Fatal("event never arrived")
 	}
 }
+
+func TestEventSource_IsActive(t *testing.T) {
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	alerts := []prom_v1.Alert{
+		{
+			ActiveAt: time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC),
+			Labels: model.LabelSet{
+				"alertname": "active-alert",
+				"pod":       "app-pod-xyxsl",
+			},
+			State: prom_v1.AlertStateFiring,
+			Value: "1",
+		},
+	}
+
+	api.On("Alerts", mock.AnythingOfType("*context.timerCtx")).Return(prom_v1.AlertsResult{
+		Alerts: alerts,
+	})
+
+	eventsource := NewEventProducer(PrometheusConfig{
+		Client: NewPrometheusService(client.API()),
+	}, nil)
+
+	active, err := eventsource.IsActive(events.Event{
+		Name: "active-alert",
+		Data: &events.EventData{"alertname": "active-alert", "pod": "app-pod-xyxsl"},
+	})
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	active, err = eventsource.IsActive(events.Event{
+		Name: "active-alert",
+		Data: &events.EventData{"alertname": "active-alert", "pod": "app-pod-other"},
+	})
+	assert.NoError(t, err)
+	assert.False(t, active)
+}
+
+func TestEventSource_pollQueries(t *testing.T) {
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	metric := model.Metric{"job": "api", "instance": "10.0.0.1:8080"}
+	api.On("Alerts", mock.AnythingOfType("*context.timerCtx")).Return(prom_v1.AlertsResult{})
+	api.On("Query", mock.Anything, "up == 0", mock.Anything).Return(model.Vector{
+		&model.Sample{Metric: metric, Value: 0},
+	})
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	eventBus := eventbus.NewEventBus(1)
+	eventBus.InjectLogger(testr.New(t))
+	go eventBus.Start(ctx)
+
+	key := manager.ToKey(metav1.ObjectMeta{Name: "prom1", Namespace: "ns1", Generation: 1})
+	eventCh, err := eventBus.Subscribe(events.CreateFullyQualifiedTopicName("InstanceDown", key.NamespacedName))
+	assert.NoError(t, err)
+
+	eventsource := NewEventProducer(PrometheusConfig{
+		Key:    key,
+		Client: NewPrometheusService(client.API()),
+		Queries: []Query{
+			{Name: "InstanceDown", Expr: "up == 0", For: 50 * time.Millisecond},
+		},
+	}, eventBus)
+
+	// the series hasn't been returned for long enough on the first poll
+	eventsource.poll()
+	select {
+	case event := <-eventCh.OnEvent():
+		t.Fatalf("unexpected event %v", event.Data)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	eventsource.poll()
+	var received events.Event
+	select {
+	case received = <-eventCh.OnEvent():
+		assert.Equal(t, "InstanceDown", received.Name)
+		assert.Equal(t, "api", received.Data.Get("job"))
+		assert.Equal(t, "0", received.Data.Get(ValueKey))
+	case <-time.After(time.Second * 5):
+		t.Fatal("event never arrived")
+	}
+
+	// published again on every poll while the series is returned
+	eventsource.poll()
+	select {
+	case event := <-eventCh.OnEvent():
+		assert.Equal(t, received.Key(), event.Key())
+	case <-time.After(time.Second * 5):
+		t.Fatal("event never arrived")
+	}
+
+	active, err := eventsource.IsActive(received)
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	received.Data = &events.EventData{"job": "web", "instance": "10.0.0.2:8080", ValueKey: "0"}
+	active, err = eventsource.IsActive(received)
+	assert.NoError(t, err)
+	assert.False(t, active)
+}
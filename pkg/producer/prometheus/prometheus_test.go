@@ -2,6 +2,16 @@ package prometheus
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -121,3 +131,110 @@ func TestGetAlerts(t *testing.T) {
 	}
 	assert.Equal(t, 2, len(events))
 }
+
+func TestQuery(t *testing.T) {
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	api.On("Query", mock.Anything, "up == 0", mock.Anything).Return(model.Vector{
+		&model.Sample{
+			Metric: model.Metric{"job": "api", "instance": "10.0.0.1:8080"},
+			Value:  0,
+		},
+	})
+	api.On("Query", mock.Anything, "scalar(sum(up))", mock.Anything).Return(&model.Scalar{Value: 2.5})
+	api.On("Query", mock.Anything, "up[5m]", mock.Anything).Return(model.Matrix{})
+
+	p := NewPrometheusService(client.API())
+
+	series, err := p.Query("up == 0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(series))
+
+	event := series[0].ToEvent("InstanceDown", time.Time{})
+	assert.Equal(t, "InstanceDown", event.Name)
+	assert.Equal(t, "api", event.Data.Get("job"))
+	assert.Equal(t, "0", event.Data.Get(ValueKey))
+	assert.True(t, series[0].Metric.Equal(metricOf(event)))
+
+	series, err = p.Query("scalar(sum(up))")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(series))
+	assert.Equal(t, "2.5", series[0].ToEvent("Up", time.Time{}).Data.Get(ValueKey))
+
+	_, err = p.Query("up[5m]")
+	assert.Error(t, err)
+}
+
+func TestNewPrometheusClient(t *testing.T) {
+	clientCert, clientKey := selfSignedCert(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) != 1 || r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1672531200,"` + r.Header.Get("X-Scope-OrgID") + `"]}}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	p, err := NewPrometheusClient(ClientConfig{
+		Address:     server.URL + "/prometheus",
+		BearerToken: "s3cr3t",
+		CA:          ca,
+		Cert:        clientCert,
+		Key:         clientKey,
+		Headers:     map[string]string{"X-Scope-OrgID": "42"},
+	})
+	assert.NoError(t, err)
+
+	series, err := p.Query("vector(1)")
+	assert.NoError(t, err)
+	if assert.Len(t, series, 1) {
+		assert.Equal(t, model.SampleValue(42), series[0].Value)
+	}
+
+	// the server isn't trusted without the CA bundle
+	p, err = NewPrometheusClient(ClientConfig{Address: server.URL, Cert: clientCert, Key: clientKey})
+	assert.NoError(t, err)
+	_, err = p.Query("vector(1)")
+	assert.Error(t, err)
+
+	_, err = NewPrometheusClient(ClientConfig{Address: server.URL, CA: []byte("not a certificate")})
+	assert.Error(t, err)
+
+	_, err = NewPrometheusClient(ClientConfig{Address: server.URL, Cert: clientCert, Key: ca})
+	assert.Error(t, err)
+}
+
+// selfSignedCert creates a PEM encoded client certificate and key
+func selfSignedCert(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "k8s-countermeasures"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
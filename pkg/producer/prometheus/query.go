@@ -0,0 +1,89 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/prometheus/common/model"
+)
+
+// ValueKey the EventData key holding the sample value of the series
+const ValueKey = "value"
+
+// Query a PromQL expression evaluated on every poll, the series it returns for longer than
+// the duration are published as events with the name.
+type Query struct {
+	Name string
+	Expr string
+	For  time.Duration
+}
+
+// Series a series returned by a query
+type Series struct {
+	Metric model.Metric
+	Value  model.SampleValue
+}
+
+// Query evaluates the PromQL expression at the current time, returning the series of an
+// instant vector or a single series without labels for a scalar.
+func (ps *PrometheusService) Query(expr string) ([]Series, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	value, _, err := ps.p8sApi.Query(ctx, expr, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error querying prometheus: %w", err)
+	}
+
+	switch v := value.(type) {
+	case model.Vector:
+		series := make([]Series, len(v))
+		for idx, sample := range v {
+			series[idx] = Series{Metric: sample.Metric, Value: sample.Value}
+		}
+		return series, nil
+	case *model.Scalar:
+		return []Series{{Metric: model.Metric{}, Value: v.Value}}, nil
+	default:
+		return nil, fmt.Errorf("query '%s' returned a %s, expected a vector or scalar", expr, value.Type())
+	}
+}
+
+// ToEvent converts the series to an Event with the name, the labels and the value of the series are
+// the data of the event.
+func (s *Series) ToEvent(name string, activeTime time.Time) events.Event {
+	data := make(events.EventData, len(s.Metric)+1)
+	for label, value := range s.Metric {
+		data[string(label)] = string(value)
+	}
+	data[ValueKey] = strconv.FormatFloat(float64(s.Value), 'f', -1, 64)
+
+	return events.Event{
+		Name:       name,
+		ActiveTime: activeTime,
+		Data:       &data,
+	}
+}
+
+// seriesKey identifies the series of a query across polls
+func seriesKey(query string, metric model.Metric) string {
+	return query + "/" + metric.Fingerprint().String()
+}
+
+// metricOf the labels of the series an event was published for
+func metricOf(event events.Event) model.Metric {
+	metric := model.Metric{}
+	if event.Data == nil {
+		return metric
+	}
+
+	for k, v := range *event.Data {
+		if k != ValueKey {
+			metric[model.LabelName(k)] = model.LabelValue(v)
+		}
+	}
+	return metric
+}
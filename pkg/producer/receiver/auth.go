@@ -0,0 +1,171 @@
+package receiver
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Authenticator checks a request is authentic
+type Authenticator interface {
+	Authenticate(ctx context.Context, header http.Header, body []byte) error
+}
+
+// NewAuthenticator creates the authenticator of the auth method, the secrets are read on every
+// request so rotating them doesn't need the event source to be updated.
+func NewAuthenticator(reader client.Reader, namespace string, auth v1alpha1.ReceiverAuth) (Authenticator, error) {
+	if err := auth.Validate(); err != nil {
+		return nil, err
+	}
+
+	if auth.BearerToken != nil {
+		return &bearerAuth{secret: secretKey{reader: reader, namespace: namespace, selector: *auth.BearerToken}}, nil
+	}
+
+	if auth.BasicAuth != nil {
+		return &basicAuth{reader: reader, key: client.ObjectKey{Namespace: namespace, Name: auth.BasicAuth.Name}}, nil
+	}
+
+	var newHash func() hash.Hash
+	switch auth.HMAC.Algorithm {
+	case "sha1":
+		newHash = sha1.New
+	case "", "sha256":
+		newHash = sha256.New
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported hmac algorithm '%s'", auth.HMAC.Algorithm)
+	}
+
+	header := auth.HMAC.Header
+	if len(header) == 0 {
+		header = "X-Signature-256"
+	}
+
+	return &hmacAuth{
+		secret:  secretKey{reader: reader, namespace: namespace, selector: auth.HMAC.SecretKeyRef},
+		header:  header,
+		prefix:  auth.HMAC.Prefix,
+		newHash: newHash,
+	}, nil
+}
+
+// secretKey reads a key of a Secret
+type secretKey struct {
+	reader    client.Reader
+	namespace string
+	selector  corev1.SecretKeySelector
+}
+
+func (s secretKey) value(ctx context.Context) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := s.reader.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: s.selector.Name}, secret); err != nil {
+		return nil, err
+	}
+
+	value, ok := secret.Data[s.selector.Key]
+	if !ok || len(value) == 0 {
+		return nil, fmt.Errorf("secret '%s' has no key '%s'", s.selector.Name, s.selector.Key)
+	}
+	return value, nil
+}
+
+// bearerAuth compares the bearer token of the Authorization header to the Secret
+type bearerAuth struct {
+	secret secretKey
+}
+
+func (a *bearerAuth) Authenticate(ctx context.Context, header http.Header, _ []byte) error {
+	token, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ErrUnauthorized
+	}
+
+	expected, err := a.secret.value(ctx)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), expected) != 1 {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// basicAuth compares the credentials of the Authorization header to a basic auth Secret
+type basicAuth struct {
+	reader client.Reader
+	key    client.ObjectKey
+}
+
+func (a *basicAuth) Authenticate(ctx context.Context, header http.Header, _ []byte) error {
+	username, password, ok := (&http.Request{Header: header}).BasicAuth()
+	if !ok {
+		return ErrUnauthorized
+	}
+
+	secret := &corev1.Secret{}
+	if err := a.reader.Get(ctx, a.key, secret); err != nil {
+		return err
+	}
+
+	if secret.Type != corev1.SecretTypeBasicAuth {
+		return fmt.Errorf("secret '%s' is of type '%s', expected '%s'", a.key.Name, secret.Type, corev1.SecretTypeBasicAuth)
+	}
+
+	// both are compared so the time taken doesn't tell which one is wrong
+	usernameMatch := subtle.ConstantTimeCompare([]byte(username), secret.Data[corev1.BasicAuthUsernameKey])
+	passwordMatch := subtle.ConstantTimeCompare([]byte(password), secret.Data[corev1.BasicAuthPasswordKey])
+	if usernameMatch&passwordMatch != 1 || len(secret.Data[corev1.BasicAuthPasswordKey]) == 0 {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// hmacAuth compares the signature header to the HMAC of the body keyed by the Secret
+type hmacAuth struct {
+	secret  secretKey
+	header  string
+	prefix  string
+	newHash func() hash.Hash
+}
+
+func (a *hmacAuth) Authenticate(ctx context.Context, header http.Header, body []byte) error {
+	signature, ok := strings.CutPrefix(header.Get(a.header), a.prefix)
+	if !ok || len(signature) == 0 {
+		return ErrUnauthorized
+	}
+
+	received, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrUnauthorized
+	}
+
+	key, err := a.secret.value(ctx)
+	if err != nil {
+		return err
+	}
+
+	mac := hmac.New(a.newHash, key)
+	mac.Write(body)
+	if !hmac.Equal(received, mac.Sum(nil)) {
+		return ErrUnauthorized
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package receiver
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const body = `{"status": "firing"}`
+
+func newSecrets() *fake.ClientBuilder {
+	return fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "webhook"},
+			Data:       map[string][]byte{"token": []byte("s3cr3t"), "key": []byte("signing-key")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "basic"},
+			Type:       corev1.SecretTypeBasicAuth,
+			Data:       map[string][]byte{"username": []byte("alertmanager"), "password": []byte("pa55")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "basic"},
+			Type:       corev1.SecretTypeBasicAuth,
+			Data:       map[string][]byte{"username": []byte("other"), "password": []byte("other")},
+		},
+	)
+}
+
+func TestNewAuthenticator_BearerToken(t *testing.T) {
+	ctx := context.TODO()
+	bearer, err := NewAuthenticator(newSecrets().Build(), "ns1", v1alpha1.ReceiverAuth{
+		BearerToken: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "webhook"},
+			Key:                  "token",
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, bearer.Authenticate(ctx, http.Header{}, []byte(body)), ErrUnauthorized)
+	assert.ErrorIs(t, bearer.Authenticate(ctx, http.Header{"Authorization": {"Bearer wrong"}}, []byte(body)), ErrUnauthorized)
+	assert.NoError(t, bearer.Authenticate(ctx, http.Header{"Authorization": {"Bearer s3cr3t"}}, []byte(body)))
+}
+
+func TestNewAuthenticator_BasicAuth(t *testing.T) {
+	ctx := context.TODO()
+	secrets := newSecrets().Build()
+	basic, err := NewAuthenticator(secrets, "ns1", v1alpha1.ReceiverAuth{
+		BasicAuth: &corev1.LocalObjectReference{Name: "basic"},
+	})
+	assert.NoError(t, err)
+
+	header := func(username, password string) http.Header {
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(username, password)
+		return req.Header
+	}
+
+	assert.ErrorIs(t, basic.Authenticate(ctx, http.Header{}, []byte(body)), ErrUnauthorized)
+	assert.ErrorIs(t, basic.Authenticate(ctx, header("alertmanager", "wrong"), []byte(body)), ErrUnauthorized)
+	assert.ErrorIs(t, basic.Authenticate(ctx, header("other", "other"), []byte(body)), ErrUnauthorized)
+	assert.NoError(t, basic.Authenticate(ctx, header("alertmanager", "pa55"), []byte(body)))
+
+	// the secret must be of the basic auth type
+	opaque, err := NewAuthenticator(secrets, "ns1", v1alpha1.ReceiverAuth{
+		BasicAuth: &corev1.LocalObjectReference{Name: "webhook"},
+	})
+	assert.NoError(t, err)
+	err = opaque.Authenticate(ctx, header("alertmanager", "pa55"), []byte(body))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestNewAuthenticator_HMAC(t *testing.T) {
+	ctx := context.TODO()
+	signed, err := NewAuthenticator(newSecrets().Build(), "ns1", v1alpha1.ReceiverAuth{
+		HMAC: &v1alpha1.HMACAuth{
+			SecretKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "webhook"},
+				Key:                  "key",
+			},
+			Header: "X-Hub-Signature-256",
+			Prefix: "sha256=",
+		},
+	})
+	assert.NoError(t, err)
+
+	mac := hmac.New(sha256.New, []byte("signing-key"))
+	mac.Write([]byte(body))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	header := http.Header{"X-Hub-Signature-256": {signature}}
+	assert.NoError(t, signed.Authenticate(ctx, header, []byte(body)))
+	assert.ErrorIs(t, signed.Authenticate(ctx, header, []byte(body+" ")), ErrUnauthorized)
+
+	header.Set("X-Hub-Signature-256", "sha256=zz")
+	assert.ErrorIs(t, signed.Authenticate(ctx, header, []byte(body)), ErrUnauthorized)
+}
+
+func TestNewAuthenticator_Invalid(t *testing.T) {
+	secrets := newSecrets().Build()
+	_, err := NewAuthenticator(secrets, "ns1", v1alpha1.ReceiverAuth{})
+	assert.Error(t, err)
+
+	_, err = NewAuthenticator(secrets, "ns1", v1alpha1.ReceiverAuth{
+		BearerToken: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "webhook"}, Key: "token"},
+		BasicAuth:   &corev1.LocalObjectReference{Name: "basic"},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// maxBodyBytes limits the size of the payloads accepted by the handlers
+const maxBodyBytes = 1 << 20
+
+// Source identifies the event source a path is served for, event sources of different kinds
+// may share a name.
+type Source struct {
+	Kind string
+	types.NamespacedName
+}
+
+type route struct {
+	source  Source
+	handler http.Handler
+}
+
+// Receiver is an HTTP server the event sources register handlers with to receive events
+// pushed to the manager, each handler is served on its own path.
+type Receiver struct {
+	address string
+
+	routesMux sync.RWMutex
+	routes    map[string]route
+}
+
+// NewReceiver creates a receiver that will listen on the address once started
+func NewReceiver(address string) *Receiver {
+	return &Receiver{
+		address: address,
+		routes:  make(map[string]route),
+	}
+}
+
+// Register serves the handler for the event source on the path, replacing any path
+// previously registered by the same event source.
+func (r *Receiver) Register(source Source, path string, handler http.Handler) error {
+	r.routesMux.Lock()
+	defer r.routesMux.Unlock()
+
+	if existing, ok := r.routes[path]; ok && existing.source != source {
+		return fmt.Errorf("path '%s' is already served for %s event source '%s'",
+			path, existing.source.Kind, existing.source.NamespacedName)
+	}
+
+	for p, rt := range r.routes {
+		if rt.source == source {
+			delete(r.routes, p)
+		}
+	}
+
+	r.routes[path] = route{source: source, handler: handler}
+	return nil
+}
+
+// Conflicts returns an error if the path is already served for another event source.
+func (r *Receiver) Conflicts(source Source, path string) error {
+	r.routesMux.RLock()
+	defer r.routesMux.RUnlock()
+
+	if existing, ok := r.routes[path]; ok && existing.source != source {
+		return fmt.Errorf("path '%s' is already served for %s event source '%s'",
+			path, existing.source.Kind, existing.source.NamespacedName)
+	}
+	return nil
+}
+
+// Unregister stops serving the paths of the event source.
+func (r *Receiver) Unregister(source Source) {
+	r.routesMux.Lock()
+	defer r.routesMux.Unlock()
+
+	for p, rt := range r.routes {
+		if rt.source == source {
+			delete(r.routes, p)
+		}
+	}
+}
+
+// ServeHTTP dispatches the request to the handler registered for the path.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.routesMux.RLock()
+	rt, ok := r.routes[req.URL.Path]
+	r.routesMux.RUnlock()
+
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	req.Body = http.MaxBytesReader(w, req.Body, maxBodyBytes)
+	rt.handler.ServeHTTP(w, req)
+}
+
+// Start satisfies the runnable interface and started by the Operator SDK manager.
+func (r *Receiver) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	server := &http.Server{
+		Addr:              r.address,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		logger.Info("stopping event receiver")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error(err, "failed to shutdown event receiver")
+		}
+	}()
+
+	logger.Info("starting event receiver", "address", r.address)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
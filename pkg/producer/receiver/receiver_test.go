@@ -0,0 +1,88 @@
+package receiver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// echo responds with the name of the handler and the size of the body it read
+type echo string
+
+func (e echo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	_, _ = io.WriteString(w, string(e)+":"+strconv.Itoa(len(body)))
+}
+
+func TestReceiver_Register(t *testing.T) {
+	rcv := NewReceiver("")
+	am1 := Source{Kind: "Alertmanager", NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "am1"}}
+	am2 := Source{Kind: "Alertmanager", NamespacedName: types.NamespacedName{Namespace: "ns2", Name: "am2"}}
+	// an event source of another kind with the same name
+	http1 := Source{Kind: "HTTPEvents", NamespacedName: am1.NamespacedName}
+
+	assert.NoError(t, rcv.Register(am1, "/alertmanager/ns1/am1", echo("am1")))
+
+	// the path is taken by another event source
+	assert.Error(t, rcv.Conflicts(am2, "/alertmanager/ns1/am1"))
+	assert.Error(t, rcv.Register(am2, "/alertmanager/ns1/am1", echo("am2")))
+	assert.NoError(t, rcv.Conflicts(am1, "/alertmanager/ns1/am1"))
+	assert.NoError(t, rcv.Conflicts(am2, "/alertmanager/ns2/am2"))
+
+	// registering again replaces the path of the event source
+	assert.NoError(t, rcv.Register(am1, "/alertmanager/ns1/renamed", echo("renamed")))
+	assert.Len(t, rcv.routes, 1)
+	assert.NoError(t, rcv.Register(am2, "/alertmanager/ns1/am1", echo("am2")))
+	assert.Error(t, rcv.Register(http1, "/alertmanager/ns1/renamed", echo("http1")))
+	assert.NoError(t, rcv.Register(http1, "/events/ns1/am1", echo("http1")))
+	assert.Len(t, rcv.routes, 3)
+
+	serve := func(method, path string, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		rcv.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	assert.Equal(t, "renamed:0", serve(http.MethodPost, "/alertmanager/ns1/renamed", "").Body.String())
+	assert.Equal(t, "am2:0", serve(http.MethodPost, "/alertmanager/ns1/am1", "").Body.String())
+
+	rcv.Unregister(am1)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/alertmanager/ns1/renamed", "").Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/alertmanager/ns1/am1", "").Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/events/ns1/am1", "").Code)
+}
+
+func TestReceiver_ServeHTTP(t *testing.T) {
+	rcv := NewReceiver("")
+	assert.NoError(t, rcv.Register(Source{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "am1"}}, "/alertmanager/ns1/am1", echo("am1")))
+
+	serve := func(method, path string, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		rcv.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/alertmanager/ns1/other", "").Code)
+
+	// only POST is accepted
+	w := serve(http.MethodGet, "/alertmanager/ns1/am1", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, http.MethodPost, w.Header().Get("Allow"))
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodPut, "/alertmanager/ns1/am1", "{}").Code)
+
+	// the body is limited in size
+	w = serve(http.MethodPost, "/alertmanager/ns1/am1", strings.Repeat("a", maxBodyBytes))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "am1:"+strconv.Itoa(maxBodyBytes), w.Body.String())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve(http.MethodPost, "/alertmanager/ns1/am1", strings.Repeat("a", maxBodyBytes+1)).Code)
+}
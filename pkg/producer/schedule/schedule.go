@@ -0,0 +1,70 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/robfig/cron/v3"
+)
+
+// ScheduledTimeKey the EventData key holding the time the event was scheduled for, it makes
+// the event of every run unique so it isn't de-duplicated with the event of the previous run.
+const ScheduledTimeKey = "scheduledTime"
+
+// Schedule the cron schedule of the event and its data
+type Schedule struct {
+	cron      cron.Schedule
+	location  *time.Location
+	eventName string
+	data      map[string]string
+}
+
+// NewSchedule parses the cron expression and timezone of the Schedule event source
+func NewSchedule(spec v1alpha1.ScheduleSpec) (*Schedule, error) {
+	location := time.UTC
+	if len(spec.Timezone) > 0 {
+		loc, err := time.LoadLocation(spec.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone '%s': %w", spec.Timezone, err)
+		}
+		location = loc
+	}
+
+	sched, err := cron.ParseStandard(spec.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule '%s': %w", spec.Schedule, err)
+	}
+
+	if len(spec.EventName) == 0 {
+		return nil, fmt.Errorf("the event name is required")
+	}
+
+	return &Schedule{
+		cron:      sched,
+		location:  location,
+		eventName: spec.EventName,
+		data:      spec.Data,
+	}, nil
+}
+
+// Next the time the event is next published after the time
+func (s *Schedule) Next(t time.Time) time.Time {
+	return s.cron.Next(t.In(s.location))
+}
+
+// ToEvent the event published for the scheduled time
+func (s *Schedule) ToEvent(scheduled time.Time) events.Event {
+	data := make(events.EventData, len(s.data)+1)
+	for k, v := range s.data {
+		data[k] = v
+	}
+	data[ScheduledTimeKey] = scheduled.Format(time.RFC3339)
+
+	return events.Event{
+		Name:       s.eventName,
+		ActiveTime: scheduled,
+		Data:       &data,
+	}
+}
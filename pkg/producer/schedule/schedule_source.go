@@ -0,0 +1,93 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/producer"
+)
+
+var scheduleLogger = ctrl.Log.WithName("schedule_eventsource")
+
+type ScheduleConfig struct {
+	Key      manager.ObjectKey
+	Schedule *Schedule
+	// OnPublish is called with the scheduled time after the event of a run is published
+	OnPublish func(time.Time)
+}
+
+// EventProducer publishes the event of the Schedule every time the cron expression is due.
+type EventProducer struct {
+	config   ScheduleConfig
+	producer producer.EventProducer
+	now      func() time.Time
+}
+
+var _ producer.KeyedEventProducer = &EventProducer{}
+
+// NewEventProducer creation function for a new Schedule EventProducer
+func NewEventProducer(cfg ScheduleConfig, prd producer.EventProducer) *EventProducer {
+	return &EventProducer{
+		config:   cfg,
+		producer: prd,
+		now:      time.Now,
+	}
+}
+
+// Start called to start publishing the event on the schedule, runs missed while the producer
+// wasn't started are not published.
+func (d *EventProducer) Start(done <-chan struct{}) error {
+	next := d.config.Schedule.Next(d.now())
+	scheduleLogger.Info("scheduling events", "name", d.getName(), "next", next)
+
+	for {
+		timer := time.NewTimer(next.Sub(d.now()))
+		select {
+		case <-done:
+			timer.Stop()
+			scheduleLogger.Info("stopped scheduling events", "name", d.getName())
+			return nil
+		case <-timer.C:
+			d.publish(next)
+			next = d.config.Schedule.Next(d.now())
+		}
+	}
+}
+
+// Publish send the event to the bus, retrying on any errors
+func (d *EventProducer) Publish(topic string, event events.Event) error {
+	return retry.OnError(retry.DefaultBackoff, func(err error) bool { return true }, func() error {
+		return d.producer.Publish(topic, event)
+	})
+}
+
+func (d *EventProducer) publish(scheduled time.Time) {
+	name := d.getName()
+	event := d.config.Schedule.ToEvent(scheduled)
+	event.Source = name
+	event.SourceKind = d.config.Key.Kind
+
+	topic := events.CreateFullyQualifiedTopicName(event.Name, name)
+	if err := d.Publish(topic, event); err != nil {
+		scheduleLogger.Error(err, fmt.Sprintf("failed to publish event %v", event.Name))
+		return
+	}
+
+	if d.config.OnPublish != nil {
+		d.config.OnPublish(scheduled)
+	}
+}
+
+func (d *EventProducer) Key() manager.ObjectKey {
+	return d.config.Key
+}
+
+func (d *EventProducer) getName() types.NamespacedName {
+	return d.config.Key.NamespacedName
+}
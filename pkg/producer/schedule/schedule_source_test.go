@@ -0,0 +1,105 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dvilaverde/k8s-countermeasures/apis/eventsource/v1alpha1"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/eventbus"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/events"
+	"github.com/dvilaverde/k8s-countermeasures/pkg/manager"
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNewSchedule(t *testing.T) {
+	sched, err := NewSchedule(v1alpha1.ScheduleSpec{
+		Schedule:  "0 3 * * *",
+		Timezone:  "America/New_York",
+		EventName: "NightlyRestart",
+		Data:      map[string]string{"deployment": "checkout"},
+	})
+	assert.NoError(t, err)
+
+	// 3am in New York is 7am UTC during daylight saving time
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	next := sched.Next(now)
+	assert.True(t, time.Date(2022, 7, 2, 7, 0, 0, 0, time.UTC).Equal(next))
+
+	event := sched.ToEvent(next)
+	assert.Equal(t, "NightlyRestart", event.Name)
+	assert.Equal(t, next, event.ActiveTime)
+	assert.Equal(t, events.EventData{"deployment": "checkout", ScheduledTimeKey: "2022-07-02T03:00:00-04:00"}, *event.Data)
+
+	// defaults to UTC
+	sched, err = NewSchedule(v1alpha1.ScheduleSpec{Schedule: "0 3 * * *", EventName: "NightlyRestart"})
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2022, 7, 2, 3, 0, 0, 0, time.UTC).Equal(sched.Next(now)))
+
+	_, err = NewSchedule(v1alpha1.ScheduleSpec{Schedule: "0 3 * *", EventName: "NightlyRestart"})
+	assert.Error(t, err)
+
+	_, err = NewSchedule(v1alpha1.ScheduleSpec{Schedule: "0 3 * * *", Timezone: "Mars/Olympus_Mons", EventName: "NightlyRestart"})
+	assert.Error(t, err)
+}
+
+func TestEventProducer_Start(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	eventBus := eventbus.NewEventBus(1)
+	eventBus.InjectLogger(testr.New(t))
+	go eventBus.Start(ctx)
+
+	source := types.NamespacedName{Namespace: "ns1", Name: "purge-cache"}
+	eventCh, err := eventBus.Subscribe(events.CreateFullyQualifiedTopicName("PurgeCache", source))
+	assert.NoError(t, err)
+
+	sched, err := NewSchedule(v1alpha1.ScheduleSpec{
+		Schedule:  "@every 1s",
+		EventName: "PurgeCache",
+		Data:      map[string]string{"cache": "sessions"},
+	})
+	assert.NoError(t, err)
+
+	published := make(chan time.Time, 10)
+	eventsource := NewEventProducer(ScheduleConfig{
+		Key:       manager.ToKey(metav1.ObjectMeta{Namespace: source.Namespace, Name: source.Name, Generation: 1}),
+		Schedule:  sched,
+		OnPublish: func(scheduled time.Time) { published <- scheduled },
+	}, eventBus)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		assert.NoError(t, eventsource.Start(done))
+	}()
+
+	select {
+	case event := <-eventCh.OnEvent():
+		assert.Equal(t, "PurgeCache", event.Name)
+		assert.Equal(t, source, event.Source)
+		assert.Equal(t, "sessions", event.Data.Get("cache"))
+		assert.Equal(t, event.ActiveTime.Format(time.RFC3339), event.Data.Get(ScheduledTimeKey))
+
+		select {
+		case scheduled := <-published:
+			assert.Equal(t, event.ActiveTime, scheduled)
+		case <-time.After(time.Second * 5):
+			t.Fatal("publish never reported")
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("event never arrived")
+	}
+
+	close(done)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second * 5):
+		t.Fatal("producer never stopped")
+	}
+}
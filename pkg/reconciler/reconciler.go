@@ -2,7 +2,7 @@ package reconciler
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -59,7 +59,8 @@ func (r *ReconcilerBase) HandleError(ctx context.Context, objectMeta metav1.Obje
 	return r.OnError(ctx, objectMeta, err, 0)
 }
 
-func (d *ReconcilerBase) GetSecret(ref *corev1.SecretReference) (corev1.Secret, error) {
+// GetSecret reads the secret, when secret types are given the secret has to be one of them
+func (d *ReconcilerBase) GetSecret(ref *corev1.SecretReference, secretTypes ...corev1.SecretType) (corev1.Secret, error) {
 	secret := corev1.Secret{}
 
 	key := client.ObjectKey{
@@ -71,12 +72,20 @@ func (d *ReconcilerBase) GetSecret(ref *corev1.SecretReference) (corev1.Secret,
 		return corev1.Secret{}, err
 	}
 
-	// TODO: support auth TLS using secret ref
-	if secret.Type != corev1.SecretTypeBasicAuth {
-		return corev1.Secret{}, errors.New("only the basic auth type (kubernetes.io/basic-auth) is currently supported")
+	if len(secretTypes) == 0 {
+		return secret, nil
 	}
 
-	return secret, nil
+	names := make([]string, len(secretTypes))
+	for idx, secretType := range secretTypes {
+		if secret.Type == secretType {
+			return secret, nil
+		}
+		names[idx] = string(secretType)
+	}
+
+	return corev1.Secret{}, fmt.Errorf("secret '%s' is of type '%s', expected one of %s",
+		ref.Name, secret.Type, strings.Join(names, ", "))
 }
 
 // GetClient returns the OperatorSDK client
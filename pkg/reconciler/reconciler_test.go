@@ -9,6 +9,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestServiceToKey(t *testing.T) {
@@ -177,3 +178,31 @@ func TestSplitKey(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSecret(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "basic"},
+			Type:       corev1.SecretTypeBasicAuth,
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "client-cert"},
+			Type:       corev1.SecretTypeTLS,
+		},
+	).Build()
+	base := NewReconcilerBase(c, nil, nil, nil, c)
+
+	secret, err := base.GetSecret(&corev1.SecretReference{Namespace: "ns", Name: "basic"}, corev1.SecretTypeBasicAuth, corev1.SecretTypeOpaque)
+	assert.NoError(t, err)
+	assert.Equal(t, "basic", secret.Name)
+
+	_, err = base.GetSecret(&corev1.SecretReference{Namespace: "ns", Name: "client-cert"}, corev1.SecretTypeBasicAuth, corev1.SecretTypeOpaque)
+	assert.EqualError(t, err, "secret 'client-cert' is of type 'kubernetes.io/tls', expected one of kubernetes.io/basic-auth, Opaque")
+
+	// any type when none are given
+	_, err = base.GetSecret(&corev1.SecretReference{Namespace: "ns", Name: "client-cert"})
+	assert.NoError(t, err)
+
+	_, err = base.GetSecret(&corev1.SecretReference{Namespace: "ns", Name: "missing"})
+	assert.Error(t, err)
+}